@@ -0,0 +1,265 @@
+package hop
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"go.hop.io/sdk/types"
+)
+
+// Clock is used to define the source of time for long-running controllers. You will generally only want to implement
+// this for testing.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel which receives the current time after the duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is a Clock which uses the system time. This is the default for everything which takes a Clock.
+type SystemClock struct{}
+
+// Now implements Clock.
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// After implements Clock.
+func (SystemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// AutoscalerConfig is used to define the configuration for an Autoscaler.
+type AutoscalerConfig struct {
+	// MinContainers is the minimum number of containers. If this is 0, it will default to 1.
+	MinContainers uint
+
+	// MaxContainers is the maximum number of containers. This must be set.
+	MaxContainers uint
+
+	// TargetCPUPercent is the average CPU usage percentage the autoscaler will try to keep the containers at. If this is 0,
+	// CPU usage will not be used to scale.
+	TargetCPUPercent float64
+
+	// TargetMemoryPercent is the average memory usage percentage the autoscaler will try to keep the containers at. If this
+	// is 0, memory usage will not be used to scale.
+	TargetMemoryPercent float64
+
+	// Tolerance is the fraction which the usage can differ from the target before any scaling happens. This stops the
+	// container count flapping around the target. If this is 0, it will default to 0.1.
+	Tolerance float64
+
+	// PollInterval is the interval between container metric polls. If this is 0, it will default to 30 seconds.
+	PollInterval time.Duration
+
+	// ScaleUpCooldown is the minimum time between the last scale and scaling up. If this is 0, it will default to 1 minute.
+	ScaleUpCooldown time.Duration
+
+	// ScaleDownCooldown is the minimum time between the last scale and scaling down. If this is 0, it will default to
+	// 5 minutes.
+	ScaleDownCooldown time.Duration
+
+	// Clock is the clock used by the autoscaler. If this is nil, the system clock is used.
+	Clock Clock
+
+	// OnScale is called after the deployment was scaled. Can be nil.
+	OnScale func(AutoscalerDecision)
+
+	// OnError is called when a poll fails within Run. The autoscaler will try again on the next poll. Can be nil.
+	OnError func(error)
+}
+
+// AutoscalerDecision is used to define the result of an autoscaler evaluation.
+type AutoscalerDecision struct {
+	// Current is the number of pending or running containers when the evaluation was made.
+	Current uint
+
+	// Target is the number of containers the autoscaler decided on.
+	Target uint
+
+	// CPUPercent is the average CPU usage percentage across the running containers with metrics.
+	CPUPercent float64
+
+	// MemoryPercent is the average memory usage percentage across the running containers with metrics.
+	MemoryPercent float64
+
+	// Scaled is true if the deployment was scaled.
+	Scaled bool
+
+	// Reason is a human-readable reason for the decision.
+	Reason string
+}
+
+// Autoscaler is used to scale a deployment based on the CPU and memory usage of its containers. Please use
+// NewAutoscaler to create this.
+type Autoscaler struct {
+	d            ClientCategoryIgniteDeployments
+	deploymentId string
+	opts         []ClientOption
+	cfg          AutoscalerConfig
+
+	mu        sync.Mutex
+	lastScale time.Time
+}
+
+// NewAutoscaler is used to create an autoscaler for a deployment. The autoscaler does nothing until Run or Evaluate is
+// called.
+func (c ClientCategoryIgniteDeployments) NewAutoscaler(
+	deploymentId string, cfg AutoscalerConfig, opts ...ClientOption,
+) (*Autoscaler, error) {
+	if cfg.MinContainers == 0 {
+		cfg.MinContainers = 1
+	}
+	if cfg.MaxContainers == 0 {
+		return nil, errors.New("max containers must be set")
+	}
+	if cfg.MinContainers > cfg.MaxContainers {
+		return nil, errors.New("min containers must not be greater than max containers")
+	}
+	if cfg.TargetCPUPercent < 0 || cfg.TargetMemoryPercent < 0 {
+		return nil, errors.New("target percentages must not be negative")
+	}
+	if cfg.TargetCPUPercent == 0 && cfg.TargetMemoryPercent == 0 {
+		return nil, errors.New("either a cpu or memory target must be set")
+	}
+	if cfg.Tolerance == 0 {
+		cfg.Tolerance = 0.1
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 30 * time.Second
+	}
+	if cfg.ScaleUpCooldown == 0 {
+		cfg.ScaleUpCooldown = time.Minute
+	}
+	if cfg.ScaleDownCooldown == 0 {
+		cfg.ScaleDownCooldown = 5 * time.Minute
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock{}
+	}
+	return &Autoscaler{d: c, deploymentId: deploymentId, opts: opts, cfg: cfg}, nil
+}
+
+// Returns the number of containers required to bring the usage to the target. Returns current if the usage is within
+// the tolerance.
+func (a *Autoscaler) desiredFor(current uint, usage, target float64) uint {
+	if target == 0 {
+		return 0
+	}
+	ratio := usage / target
+	if math.Abs(ratio-1) <= a.cfg.Tolerance {
+		return current
+	}
+	return uint(math.Ceil(float64(current) * ratio))
+}
+
+// Evaluate is used to poll the containers of the deployment once and scale it if required.
+func (a *Autoscaler) Evaluate(ctx context.Context) (AutoscalerDecision, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	containers, err := a.d.GetContainers(ctx, a.deploymentId, a.opts...)
+	if err != nil {
+		return AutoscalerDecision{}, err
+	}
+
+	// Work out the current count and the average usage.
+	var decision AutoscalerDecision
+	withMetrics := 0
+	for _, v := range containers {
+		switch v.State {
+		case types.ContainerStatePending:
+			decision.Current++
+		case types.ContainerStateRunning:
+			decision.Current++
+			if v.Metrics != nil {
+				withMetrics++
+				decision.CPUPercent += v.Metrics.CPUUsagePercent
+				decision.MemoryPercent += v.Metrics.MemoryUsagePercent
+			}
+		}
+	}
+	if withMetrics != 0 {
+		decision.CPUPercent /= float64(withMetrics)
+		decision.MemoryPercent /= float64(withMetrics)
+	}
+
+	// Work out the target count.
+	decision.Target = decision.Current
+	switch {
+	case decision.Current < a.cfg.MinContainers:
+		decision.Target = a.cfg.MinContainers
+		decision.Reason = "below minimum containers"
+	case decision.Current > a.cfg.MaxContainers:
+		decision.Target = a.cfg.MaxContainers
+		decision.Reason = "above maximum containers"
+	case withMetrics == 0:
+		decision.Reason = "no container metrics available"
+		return decision, nil
+	default:
+		cpuTarget := a.desiredFor(decision.Current, decision.CPUPercent, a.cfg.TargetCPUPercent)
+		memTarget := a.desiredFor(decision.Current, decision.MemoryPercent, a.cfg.TargetMemoryPercent)
+		if a.cfg.TargetCPUPercent == 0 {
+			cpuTarget = memTarget
+		}
+		if a.cfg.TargetMemoryPercent == 0 {
+			memTarget = cpuTarget
+		}
+		decision.Target = cpuTarget
+		if memTarget > decision.Target {
+			decision.Target = memTarget
+		}
+		if decision.Target < a.cfg.MinContainers {
+			decision.Target = a.cfg.MinContainers
+		}
+		if decision.Target > a.cfg.MaxContainers {
+			decision.Target = a.cfg.MaxContainers
+		}
+
+		// Check the cooldowns.
+		sinceLastScale := a.cfg.Clock.Now().Sub(a.lastScale)
+		switch {
+		case decision.Target == decision.Current:
+			decision.Reason = "usage within tolerance of target"
+			return decision, nil
+		case decision.Target > decision.Current && !a.lastScale.IsZero() && sinceLastScale < a.cfg.ScaleUpCooldown:
+			decision.Reason = "scale up cooldown active"
+			decision.Target = decision.Current
+			return decision, nil
+		case decision.Target < decision.Current && !a.lastScale.IsZero() && sinceLastScale < a.cfg.ScaleDownCooldown:
+			decision.Reason = "scale down cooldown active"
+			decision.Target = decision.Current
+			return decision, nil
+		case decision.Target > decision.Current:
+			decision.Reason = "usage above target"
+		default:
+			decision.Reason = "usage below target"
+		}
+	}
+
+	// Scale the deployment.
+	if _, err = a.d.Scale(ctx, a.deploymentId, decision.Target, a.opts...); err != nil {
+		return decision, err
+	}
+	a.lastScale = a.cfg.Clock.Now()
+	decision.Scaled = true
+	if a.cfg.OnScale != nil {
+		a.cfg.OnScale(decision)
+	}
+	return decision, nil
+}
+
+// Run is used to evaluate the deployment every poll interval until the context is cancelled. Errors from polling are
+// passed to OnError and do not stop the autoscaler. The context error is returned when the context is done.
+func (a *Autoscaler) Run(ctx context.Context) error {
+	for {
+		if _, err := a.Evaluate(ctx); err != nil && ctx.Err() == nil && a.cfg.OnError != nil {
+			a.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-a.cfg.Clock.After(a.cfg.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package hop
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	afters []chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.afters = append(c.afters, ch)
+	return ch
+}
+
+// Moves the clock forward and fires any waiting After channels.
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	afters := c.afters
+	c.afters = nil
+	now := c.now
+	c.mu.Unlock()
+	for _, ch := range afters {
+		ch <- now
+	}
+}
+
+func (c *fakeClock) waiting() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.afters)
+}
+
+func runningContainer(cpu, mem float64) *types.Container {
+	return &types.Container{
+		State:   types.ContainerStateRunning,
+		Metrics: &types.ContainerMetrics{CPUUsagePercent: cpu, MemoryUsagePercent: mem},
+	}
+}
+
+// Makes a doer which returns the containers specified and records any scales.
+func autoscalerDoer(t *testing.T, containers *[]*types.Container, scales *[]uint) *funcClientDoer {
+	t.Helper()
+	return &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		switch a.Path {
+		case "/ignite/deployments/test/containers":
+			setResult(a, *containers)
+		case "/ignite/deployments/test/scale":
+			body, ok := a.Body.(map[string]uint)
+			require.True(t, ok)
+			*scales = append(*scales, body["scale"])
+			setResult(a, []*types.Container{})
+		default:
+			t.Fatalf("unexpected path %s", a.Path)
+		}
+		return nil
+	}}
+}
+
+func TestClient_Ignite_Deployments_NewAutoscaler(t *testing.T) {
+	tests := []struct {
+		name string
+
+		cfg     AutoscalerConfig
+		wantErr string
+	}{
+		{
+			name:    "no max",
+			cfg:     AutoscalerConfig{TargetCPUPercent: 50},
+			wantErr: "max containers must be set",
+		},
+		{
+			name:    "min above max",
+			cfg:     AutoscalerConfig{MinContainers: 3, MaxContainers: 2, TargetCPUPercent: 50},
+			wantErr: "min containers must not be greater than max containers",
+		},
+		{
+			name:    "negative target",
+			cfg:     AutoscalerConfig{MaxContainers: 2, TargetCPUPercent: -1},
+			wantErr: "target percentages must not be negative",
+		},
+		{
+			name:    "no targets",
+			cfg:     AutoscalerConfig{MaxContainers: 2},
+			wantErr: "either a cpu or memory target must be set",
+		},
+		{
+			name: "defaults",
+			cfg:  AutoscalerConfig{MaxContainers: 2, TargetMemoryPercent: 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := (ClientCategoryIgniteDeployments{}).NewAutoscaler("test", tt.cfg)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(1), a.cfg.MinContainers)
+			assert.Equal(t, 0.1, a.cfg.Tolerance)
+			assert.Equal(t, 30*time.Second, a.cfg.PollInterval)
+			assert.Equal(t, time.Minute, a.cfg.ScaleUpCooldown)
+			assert.Equal(t, 5*time.Minute, a.cfg.ScaleDownCooldown)
+			assert.Equal(t, SystemClock{}, a.cfg.Clock)
+		})
+	}
+}
+
+func TestAutoscaler_Evaluate(t *testing.T) {
+	tests := []struct {
+		name string
+
+		cfg        AutoscalerConfig
+		containers []*types.Container
+		lastScale  time.Duration // relative to the clock, 0 for never scaled
+
+		wantTarget uint
+		wantScaled bool
+		wantReason string
+	}{
+		{
+			name:       "below minimum",
+			cfg:        AutoscalerConfig{MinContainers: 2, MaxContainers: 5, TargetCPUPercent: 50},
+			containers: []*types.Container{runningContainer(10, 10)},
+			lastScale:  -time.Second,
+			wantTarget: 2,
+			wantScaled: true,
+			wantReason: "below minimum containers",
+		},
+		{
+			name:       "no metrics",
+			cfg:        AutoscalerConfig{MaxContainers: 5, TargetCPUPercent: 50},
+			containers: []*types.Container{{State: types.ContainerStateRunning}},
+			wantTarget: 1,
+			wantReason: "no container metrics available",
+		},
+		{
+			name:       "within tolerance",
+			cfg:        AutoscalerConfig{MaxContainers: 5, TargetCPUPercent: 50},
+			containers: []*types.Container{runningContainer(52, 0), runningContainer(50, 0)},
+			wantTarget: 2,
+			wantReason: "usage within tolerance of target",
+		},
+		{
+			name: "cpu scale up",
+			cfg:  AutoscalerConfig{MaxContainers: 10, TargetCPUPercent: 50},
+			containers: []*types.Container{
+				runningContainer(90, 0), runningContainer(80, 0),
+				{State: types.ContainerStateStopped},
+			},
+			wantTarget: 4,
+			wantScaled: true,
+			wantReason: "usage above target",
+		},
+		{
+			name:       "memory wins over cpu",
+			cfg:        AutoscalerConfig{MaxContainers: 10, TargetCPUPercent: 50, TargetMemoryPercent: 40},
+			containers: []*types.Container{runningContainer(50, 100), runningContainer(50, 100)},
+			wantTarget: 5,
+			wantScaled: true,
+			wantReason: "usage above target",
+		},
+		{
+			name:       "capped at maximum",
+			cfg:        AutoscalerConfig{MaxContainers: 3, TargetCPUPercent: 10},
+			containers: []*types.Container{runningContainer(100, 0), runningContainer(100, 0)},
+			wantTarget: 3,
+			wantScaled: true,
+			wantReason: "usage above target",
+		},
+		{
+			name: "scale down",
+			cfg:  AutoscalerConfig{MaxContainers: 10, TargetCPUPercent: 50},
+			containers: []*types.Container{
+				runningContainer(10, 0), runningContainer(10, 0),
+				runningContainer(10, 0), runningContainer(10, 0),
+			},
+			lastScale:  -10 * time.Minute,
+			wantTarget: 1,
+			wantScaled: true,
+			wantReason: "usage below target",
+		},
+		{
+			name:       "scale up cooldown",
+			cfg:        AutoscalerConfig{MaxContainers: 10, TargetCPUPercent: 50},
+			containers: []*types.Container{runningContainer(100, 0)},
+			lastScale:  -30 * time.Second,
+			wantTarget: 1,
+			wantReason: "scale up cooldown active",
+		},
+		{
+			name:       "scale down cooldown",
+			cfg:        AutoscalerConfig{MaxContainers: 10, TargetCPUPercent: 50},
+			containers: []*types.Container{runningContainer(1, 0), runningContainer(1, 0)},
+			lastScale:  -2 * time.Minute,
+			wantTarget: 2,
+			wantReason: "scale down cooldown active",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+			var scales []uint
+			c := autoscalerDoer(t, &tt.containers, &scales)
+			var scaled []AutoscalerDecision
+			tt.cfg.Clock = clock
+			tt.cfg.OnScale = func(d AutoscalerDecision) { scaled = append(scaled, d) }
+			a, err := (ClientCategoryIgniteDeployments{c: c}).NewAutoscaler("test", tt.cfg)
+			require.NoError(t, err)
+			if tt.lastScale != 0 {
+				a.lastScale = clock.now.Add(tt.lastScale)
+			}
+
+			d, err := a.Evaluate(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTarget, d.Target)
+			assert.Equal(t, tt.wantScaled, d.Scaled)
+			assert.Equal(t, tt.wantReason, d.Reason)
+			if tt.wantScaled {
+				assert.Equal(t, []uint{tt.wantTarget}, scales)
+				assert.Equal(t, []AutoscalerDecision{d}, scaled)
+				assert.Equal(t, clock.now, a.lastScale)
+			} else {
+				assert.Empty(t, scales)
+				assert.Empty(t, scaled)
+			}
+		})
+	}
+}
+
+func TestAutoscaler_Run(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var mu sync.Mutex
+	polls := 0
+	var scales []uint
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if a.Path == "/ignite/deployments/test/scale" {
+			body, ok := a.Body.(map[string]uint)
+			require.True(t, ok)
+			scales = append(scales, body["scale"])
+			setResult(a, []*types.Container{})
+			return nil
+		}
+		polls++
+		if polls == 2 {
+			return errors.New("cat tripped on wire")
+		}
+		setResult(a, []*types.Container{runningContainer(100, 0)})
+		return nil
+	}}
+
+	errCh := make(chan error, 10)
+	a, err := (ClientCategoryIgniteDeployments{c: c}).NewAutoscaler("test", AutoscalerConfig{
+		MaxContainers:    10,
+		TargetCPUPercent: 50,
+		Clock:            clock,
+		OnError:          func(err error) { errCh <- err },
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+
+	// Wait for each poll to finish and move the clock forward.
+	waitForPoll := func() {
+		t.Helper()
+		assert.Eventually(t, func() bool { return clock.waiting() == 1 }, time.Second, time.Millisecond)
+	}
+	waitForPoll()
+	clock.advance(30 * time.Second)
+	waitForPoll()
+	assert.EqualError(t, <-errCh, "cat tripped on wire")
+	clock.advance(15 * time.Second)
+	waitForPoll()
+	clock.advance(time.Minute)
+	waitForPoll()
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 4, polls)
+
+	// The first poll scales, the third is in the cooldown and the fourth is after it.
+	assert.Equal(t, []uint{2, 2}, scales)
+}
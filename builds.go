@@ -0,0 +1,322 @@
+package hop
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.hop.io/sdk/types"
+)
+
+// GetAll is used to get all the builds of a deployment.
+func (c ClientCategoryIgniteBuilds) GetAll(ctx context.Context, deploymentId string, opts ...ClientOption) ([]*types.Build, error) {
+	var builds []*types.Build
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      "/ignite/deployments/" + url.PathEscape(deploymentId) + "/builds",
+		ResultKey: "builds",
+		Result:    &builds,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return builds, nil
+}
+
+// Get is used to get a build by its ID.
+func (c ClientCategoryIgniteBuilds) Get(ctx context.Context, id string, opts ...ClientOption) (*types.Build, error) {
+	var b types.Build
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      "/ignite/builds/" + url.PathEscape(id),
+		ResultKey: "build",
+		Result:    &b,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Cancel is used to cancel a running build by its ID.
+func (c ClientCategoryIgniteBuilds) Cancel(ctx context.Context, id string, opts ...ClientOption) error {
+	return c.c.do(ctx, ClientArgs{
+		Method: "POST",
+		Path:   "/ignite/builds/" + url.PathEscape(id) + "/cancel",
+	}, opts)
+}
+
+// GetLogs is used to stream the logs of a build. The stream stays open until the build finishes or the context
+// is cancelled. The reader MUST be closed by the caller.
+func (c ClientCategoryIgniteBuilds) GetLogs(ctx context.Context, id string, opts ...ClientOption) (io.ReadCloser, error) {
+	var resp *http.Response
+	err := c.c.do(ctx, ClientArgs{
+		Method:      "GET",
+		Path:        "/ignite/builds/" + url.PathEscape(id) + "/logs",
+		Query:       map[string]string{"stream": "true"},
+		PassRequest: func(r *http.Response) { resp = r },
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// CreateFromDirectory is used to upload a local directory as a tarball to trigger a build using the CLI build method.
+// If the deployment has a root directory set in its build settings, only that directory will be uploaded. Files
+// matching the patterns in a .hopignore file (or a .dockerignore file if that is not present) inside the uploaded
+// directory are excluded.
+func (c ClientCategoryIgniteBuilds) CreateFromDirectory(
+	ctx context.Context, deploymentId, dir string, opts ...ClientOption,
+) (*types.Build, error) {
+	// Get the deployment to find the build settings.
+	d, err := ClientCategoryIgniteDeployments{c: c.c}.Get(ctx, deploymentId, opts...)
+	if err != nil {
+		return nil, err
+	}
+	root := dir
+	if d.BuildSettings != nil && d.BuildSettings.RootDirectory != "" {
+		root = filepath.Join(dir, filepath.FromSlash(d.BuildSettings.RootDirectory))
+		rel, relErr := filepath.Rel(dir, root)
+		if relErr != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, errors.New("build root directory is outside of the directory specified")
+		}
+	}
+
+	// Load the ignore file.
+	ignore, err := loadIgnoreFile(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stream the tarball into a multipart body.
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		writeErr := mw.WriteField("method", string(types.BuildMethodCLI))
+		var part io.Writer
+		if writeErr == nil {
+			part, writeErr = mw.CreateFormFile("file", "build.tar.gz")
+		}
+		if writeErr == nil {
+			writeErr = writeBuildTarball(part, root, ignore)
+		}
+		if writeErr == nil {
+			writeErr = mw.Close()
+		}
+		_ = pw.CloseWithError(writeErr)
+	}()
+
+	var b types.Build
+	err = c.c.do(ctx, ClientArgs{
+		Method:    "POST",
+		Path:      "/ignite/deployments/" + url.PathEscape(deploymentId) + "/builds",
+		Body:      ReaderBody{ContentType: mw.FormDataContentType(), Reader: pr},
+		ResultKey: "build",
+		Result:    &b,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Writes a gzipped tarball of the root specified to the writer, skipping anything the ignore rules match.
+func writeBuildTarball(w io.Writer, root string, ignore *ignoreRules) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if ignore.matches(rel) {
+			if d.IsDir() && !ignore.hasExceptions() {
+				// Nothing inside can be re-included, so skip the whole directory.
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		_ = f.Close()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+type ignoreRule struct {
+	re        *regexp.Regexp
+	exception bool
+}
+
+// Defines the rules from a .hopignore or .dockerignore file. The rules follow the .dockerignore semantics where the
+// last matching rule wins, rules starting with ! are exceptions, and ** matches any number of directories.
+type ignoreRules struct {
+	rules []ignoreRule
+}
+
+// Loads the ignore rules from the directory specified. Returns empty rules if there is no ignore file.
+func loadIgnoreFile(dir string) (*ignoreRules, error) {
+	for _, name := range []string{".hopignore", ".dockerignore"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		rules, err := parseIgnoreRules(f)
+		_ = f.Close()
+		return rules, err
+	}
+	return &ignoreRules{}, nil
+}
+
+// Parses the ignore rules from the reader specified.
+func parseIgnoreRules(r io.Reader) (*ignoreRules, error) {
+	rules := &ignoreRules{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		exception := false
+		if strings.HasPrefix(line, "!") {
+			exception = true
+			line = strings.TrimSpace(line[1:])
+		}
+		line = strings.Trim(filepath.ToSlash(filepath.Clean(line)), "/")
+		if line == "" || line == "." {
+			continue
+		}
+		re, err := ignorePatternToRegexp(line)
+		if err != nil {
+			return nil, err
+		}
+		rules.rules = append(rules.rules, ignoreRule{re: re, exception: exception})
+	}
+	return rules, s.Err()
+}
+
+// Turns a ignore pattern into a regular expression. The pattern also matches everything inside the path.
+func ignorePatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		switch ch {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// **/ matches zero or more directories.
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end == -1 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	sb.WriteString("(?:/.*)?$")
+	return regexp.Compile(sb.String())
+}
+
+// Returns if the slash separated relative path should be ignored.
+func (r *ignoreRules) matches(p string) bool {
+	ignored := false
+	for _, rule := range r.rules {
+		if rule.re.MatchString(p) {
+			ignored = !rule.exception
+		}
+	}
+	return ignored
+}
+
+// Returns if there are any exception rules.
+func (r *ignoreRules) hasExceptions() bool {
+	for _, rule := range r.rules {
+		if rule.exception {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package hop
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+func TestClient_Ignite_Builds_GetAll(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "GET",
+		wantPath:      "/ignite/deployments/test%20test/builds",
+		wantResultKey: "builds",
+		wantIgnore404: false,
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteBuilds{c: c},
+		"GetAll",
+		[]any{"test test"},
+		[]*types.Build{{ID: "hello"}})
+}
+
+func TestClient_Ignite_Builds_Get(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "GET",
+		wantPath:      "/ignite/builds/test%20test",
+		wantResultKey: "build",
+		wantIgnore404: false,
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteBuilds{c: c},
+		"Get",
+		[]any{"test test"},
+		&types.Build{ID: "hello"})
+}
+
+func TestClient_Ignite_Builds_Cancel(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "POST",
+		wantPath:      "/ignite/builds/test%20test/cancel",
+		wantIgnore404: false,
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteBuilds{c: c},
+		"Cancel",
+		[]any{"test test"},
+		nil)
+}
+
+func TestClient_Ignite_Builds_GetLogs(t *testing.T) {
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		assert.Equal(t, "GET", a.Method)
+		assert.Equal(t, "/ignite/builds/test%20test/logs", a.Path)
+		assert.Equal(t, map[string]string{"stream": "true"}, a.Query)
+		a.PassRequest(&http.Response{Body: io.NopCloser(strings.NewReader("step 1/2\nstep 2/2\n"))})
+		return nil
+	}}
+	rc, err := (&ClientCategoryIgniteBuilds{c: c}).GetLogs(context.Background(), "test test")
+	require.NoError(t, err)
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.NoError(t, rc.Close())
+	assert.Equal(t, "step 1/2\nstep 2/2\n", string(b))
+}
+
+func Test_ignoreRules_matches(t *testing.T) {
+	type pathCase struct {
+		path    string
+		ignored bool
+	}
+	tests := []struct {
+		name string
+
+		rules string
+		paths []pathCase
+	}{
+		{
+			name:  "no rules",
+			rules: "",
+			paths: []pathCase{{"a", false}, {"a/b", false}},
+		},
+		{
+			name:  "comments and blank lines",
+			rules: "# comment\n\n  \nnode_modules\n",
+			paths: []pathCase{
+				{"src/index.js", false},
+				{"node_modules_old", false},
+				{"node_modules", true},
+				{"node_modules/x/y.js", true},
+			},
+		},
+		{
+			name:  "wildcards",
+			rules: "*.log\ntmp?",
+			paths: []pathCase{
+				{"logs/a.log", false},
+				{"tmp", false},
+				{"tmp12", false},
+				{"a.log", true},
+				{"tmp1", true},
+				{"tmp1/a", true},
+			},
+		},
+		{
+			name:  "double star",
+			rules: "**/*.log\ndocs/**",
+			paths: []pathCase{
+				{"docs", false},
+				{"a.txt", false},
+				{"a.log", true},
+				{"x/y/z.log", true},
+				{"docs/a/b.md", true},
+			},
+		},
+		{
+			name:  "exceptions",
+			rules: "*.md\n!README.md",
+			paths: []pathCase{{"README.md", false}, {"src/a.md", false}, {"CHANGELOG.md", true}},
+		},
+		{
+			name:  "leading slash and character classes",
+			rules: "/build\n[ab].txt",
+			paths: []pathCase{
+				{"src/build", false},
+				{"c.txt", false},
+				{"build/out", true},
+				{"a.txt", true},
+				{"b.txt", true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := parseIgnoreRules(strings.NewReader(tt.rules))
+			require.NoError(t, err)
+			for _, p := range tt.paths {
+				assert.Equal(t, p.ignored, r.matches(p.path), p.path)
+			}
+		})
+	}
+}
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+}
+
+func TestClient_Ignite_Builds_CreateFromDirectory(t *testing.T) {
+	tests := []struct {
+		name string
+
+		files         map[string]string
+		buildSettings *types.ContainerBuildSettings
+
+		wantErr   string
+		wantFiles map[string]string
+	}{
+		{
+			name: "whole directory with hopignore",
+			files: map[string]string{
+				".hopignore":              "node_modules\n*.log\n",
+				".dockerignore":           "src",
+				"Dockerfile":              "FROM scratch",
+				"src/main.go":             "package main",
+				"debug.log":               "oops",
+				"node_modules/a/index.js": "x",
+			},
+			wantFiles: map[string]string{
+				".hopignore":    "node_modules\n*.log\n",
+				".dockerignore": "src",
+				"Dockerfile":    "FROM scratch",
+				"src/main.go":   "package main",
+			},
+		},
+		{
+			name: "root directory with dockerignore",
+			files: map[string]string{
+				"README.md":           "hello",
+				"app/.dockerignore":   "*.md\n!KEEP.md",
+				"app/Dockerfile":      "FROM scratch",
+				"app/NOTES.md":        "notes",
+				"app/KEEP.md":         "keep",
+				"app/internal/x/y.go": "package x",
+			},
+			buildSettings: &types.ContainerBuildSettings{RootDirectory: "app"},
+			wantFiles: map[string]string{
+				".dockerignore":   "*.md\n!KEEP.md",
+				"Dockerfile":      "FROM scratch",
+				"KEEP.md":         "keep",
+				"internal/x/y.go": "package x",
+			},
+		},
+		{
+			name:          "root directory escapes",
+			files:         map[string]string{"Dockerfile": "FROM scratch"},
+			buildSettings: &types.ContainerBuildSettings{RootDirectory: "../other"},
+			wantErr:       "build root directory is outside of the directory specified",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, tt.files)
+
+			calls := 0
+			gotFiles := map[string]string{}
+			c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+				calls++
+				if calls == 1 {
+					assert.Equal(t, "/ignite/deployments/test", a.Path)
+					setResult(a, types.Deployment{ID: "test", BuildSettings: tt.buildSettings})
+					return nil
+				}
+
+				assert.Equal(t, "POST", a.Method)
+				assert.Equal(t, "/ignite/deployments/test/builds", a.Path)
+				assert.Equal(t, "build", a.ResultKey)
+				body, ok := a.Body.(ReaderBody)
+				require.True(t, ok)
+				_, params, err := mime.ParseMediaType(body.ContentType)
+				require.NoError(t, err)
+
+				mr := multipart.NewReader(body.Reader, params["boundary"])
+				part, err := mr.NextPart()
+				require.NoError(t, err)
+				assert.Equal(t, "method", part.FormName())
+				method, _ := io.ReadAll(part)
+				assert.Equal(t, "cli", string(method))
+
+				part, err = mr.NextPart()
+				require.NoError(t, err)
+				assert.Equal(t, "file", part.FormName())
+				gz, err := gzip.NewReader(part)
+				require.NoError(t, err)
+				tr := tar.NewReader(gz)
+				for {
+					hdr, nextErr := tr.Next()
+					if nextErr == io.EOF {
+						break
+					}
+					require.NoError(t, nextErr)
+					if hdr.Typeflag != tar.TypeReg {
+						continue
+					}
+					b, readErr := io.ReadAll(tr)
+					require.NoError(t, readErr)
+					gotFiles[hdr.Name] = string(b)
+				}
+				setResult(a, types.Build{ID: "build_1", Method: types.BuildMethodCLI})
+				return nil
+			}}
+
+			b, err := (&ClientCategoryIgniteBuilds{c: c}).CreateFromDirectory(context.Background(), "test", dir)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &types.Build{ID: "build_1", Method: types.BuildMethodCLI}, b)
+			assert.Equal(t, tt.wantFiles, gotFiles)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package hop
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBulkConcurrency is the number of requests a bulk operation makes at once if WithConcurrency is not used.
+const DefaultBulkConcurrency = 10
+
+// BulkResult is the result of a single item within a bulk operation.
+type BulkResult struct {
+	// ID is the ID of the item.
+	ID string
+
+	// Err is the error from the item. This is nil if the item succeeded.
+	Err error
+}
+
+// BulkError is returned by bulk operations when any of the items failed. The other items are still processed.
+type BulkError struct {
+	// Total is the total number of items in the operation.
+	Total int
+
+	// Failed is the items which failed in the order they were given.
+	Failed []BulkResult
+}
+
+// Error implements the error interface.
+func (b *BulkError) Error() string {
+	s := make([]string, len(b.Failed))
+	for i, v := range b.Failed {
+		s[i] = v.ID + ": " + v.Err.Error()
+	}
+	return strconv.Itoa(len(b.Failed)) + " of " + strconv.Itoa(b.Total) + " items failed (" + strings.Join(s, ", ") + ")"
+}
+
+// FailedIDs returns the IDs of the items which failed.
+func (b *BulkError) FailedIDs() []string {
+	ids := make([]string, len(b.Failed))
+	for i, v := range b.Failed {
+		ids[i] = v.ID
+	}
+	return ids
+}
+
+// Returns the concurrency from the client options.
+func getConcurrency(opts []ClientOption) int {
+	concurrency := DefaultBulkConcurrency
+	for _, v := range opts {
+		if x, ok := v.(concurrencyOption); ok {
+			concurrency = x.n
+		}
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return concurrency
+}
+
+// Runs the function for each ID with the concurrency from the client options. Returns the result of every item in the
+// order of the IDs, and a *BulkError if any of them failed. Items which have not started when the context is cancelled
+// fail with the context error.
+func runBulk(ctx context.Context, ids []string, opts []ClientOption, fn func(id string) error) ([]BulkResult, error) {
+	results := make([]BulkResult, len(ids))
+	sem := make(chan struct{}, getConcurrency(opts))
+	wg := sync.WaitGroup{}
+	for i, id := range ids {
+		results[i].ID = id
+		select {
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+		if err := ctx.Err(); err != nil {
+			// Both were ready, so make sure nothing is started after the context is cancelled.
+			<-sem
+			results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i].Err = fn(id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	var failed []BulkResult
+	for _, v := range results {
+		if v.Err != nil {
+			failed = append(failed, v)
+		}
+	}
+	if failed != nil {
+		return results, &BulkError{Total: len(ids), Failed: failed}
+	}
+	return results, nil
+}
+
+// SubscribeTokensBulk is used to subscribe many tokens to a channel. Use WithConcurrency to change how many tokens
+// are subscribed at once. Returns the result of each token, and a *BulkError if any of them failed.
+func (c ClientCategoryChannels) SubscribeTokensBulk(
+	ctx context.Context, channelId string, tokens []string, opts ...ClientOption,
+) ([]BulkResult, error) {
+	return runBulk(ctx, tokens, opts, func(token string) error {
+		return c.SubscribeToken(ctx, channelId, token, opts...)
+	})
+}
+
+// RemoveTokensBulk is used to remove many tokens from a channel. Use WithConcurrency to change how many tokens are
+// removed at once. Returns the result of each token, and a *BulkError if any of them failed.
+func (c ClientCategoryChannels) RemoveTokensBulk(
+	ctx context.Context, channelId string, tokens []string, opts ...ClientOption,
+) ([]BulkResult, error) {
+	return runBulk(ctx, tokens, opts, func(token string) error {
+		return c.RemoveToken(ctx, channelId, token, opts...)
+	})
+}
+
+// PublishMessageBulk is used to publish the same event to many channels. Use WithConcurrency to change how many
+// channels are published to at once. Returns the result of each channel, and a *BulkError if any of them failed.
+func (c ClientCategoryChannels) PublishMessageBulk(
+	ctx context.Context, channelIds []string, eventName string, data any, opts ...ClientOption,
+) ([]BulkResult, error) {
+	return runBulk(ctx, channelIds, opts, func(channelId string) error {
+		return c.PublishMessage(ctx, channelId, eventName, data, opts...)
+	})
+}
+
+// DeleteBulk is used to delete many channels. Use WithConcurrency to change how many channels are deleted at once.
+// Returns the result of each channel, and a *BulkError if any of them failed.
+func (c ClientCategoryChannels) DeleteBulk(ctx context.Context, ids []string, opts ...ClientOption) ([]BulkResult, error) {
+	return runBulk(ctx, ids, opts, func(id string) error {
+		return c.Delete(ctx, id, opts...)
+	})
+}
+
+// DeleteBulk is used to delete many channel tokens. Use WithConcurrency to change how many tokens are deleted at once.
+// Returns the result of each token, and a *BulkError if any of them failed.
+func (t ClientCategoryChannelsTokens) DeleteBulk(ctx context.Context, ids []string, opts ...ClientOption) ([]BulkResult, error) {
+	return runBulk(ctx, ids, opts, func(id string) error {
+		return t.Delete(ctx, id, opts...)
+	})
+}
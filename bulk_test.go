@@ -0,0 +1,147 @@
+package hop
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+func Test_runBulk(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e", "f"}
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	results, err := runBulk(context.Background(), ids, []ClientOption{WithConcurrency(2)}, func(id string) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		if id == "b" || id == "e" {
+			return errors.New("failed " + id)
+		}
+		return nil
+	})
+
+	assert.LessOrEqual(t, maxRunning, 2)
+	assert.Equal(t, []BulkResult{
+		{ID: "a"}, {ID: "b", Err: errors.New("failed b")}, {ID: "c"},
+		{ID: "d"}, {ID: "e", Err: errors.New("failed e")}, {ID: "f"},
+	}, results)
+	var bulkErr *BulkError
+	require.ErrorAs(t, err, &bulkErr)
+	assert.Equal(t, []string{"b", "e"}, bulkErr.FailedIDs())
+	assert.EqualError(t, err, "2 of 6 items failed (b: failed b, e: failed e)")
+
+	results, err = runBulk(context.Background(), ids, nil, func(string) error { return nil })
+	assert.NoError(t, err)
+	assert.Len(t, results, 6)
+}
+
+func Test_runBulk_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := runBulk(ctx, []string{"a", "b"}, []ClientOption{WithConcurrency(1)}, func(string) error {
+		cancel()
+		return nil
+	})
+	assert.Equal(t, []BulkResult{{ID: "a"}, {ID: "b", Err: context.Canceled}}, results)
+	assert.EqualError(t, err, "1 of 2 items failed (b: context canceled)")
+}
+
+func TestClient_Channels_Bulk(t *testing.T) {
+	tests := []struct {
+		name string
+
+		fn         func(c clientDoer) ([]BulkResult, error)
+		wantMethod string
+		wantPaths  []string
+		wantBody   any
+	}{
+		{
+			name: "subscribe tokens",
+			fn: func(c clientDoer) ([]BulkResult, error) {
+				return ClientCategoryChannels{c: c}.SubscribeTokensBulk(context.Background(), "channel", []string{"a", "b"})
+			},
+			wantMethod: "PUT",
+			wantPaths:  []string{"/channels/channel/subscribers/a", "/channels/channel/subscribers/b"},
+		},
+		{
+			name: "remove tokens",
+			fn: func(c clientDoer) ([]BulkResult, error) {
+				return ClientCategoryChannels{c: c}.RemoveTokensBulk(context.Background(), "channel", []string{"a", "b"})
+			},
+			wantMethod: "DELETE",
+			wantPaths:  []string{"/channels/channel/subscribers/a", "/channels/channel/subscribers/b"},
+		},
+		{
+			name: "publish message",
+			fn: func(c clientDoer) ([]BulkResult, error) {
+				return ClientCategoryChannels{c: c}.PublishMessageBulk(context.Background(), []string{"a", "b"}, "hello", "world")
+			},
+			wantMethod: "POST",
+			wantPaths:  []string{"/channels/a/messages", "/channels/b/messages"},
+			wantBody:   map[string]any{"e": "hello", "d": "world"},
+		},
+		{
+			name: "delete channels",
+			fn: func(c clientDoer) ([]BulkResult, error) {
+				return ClientCategoryChannels{c: c}.DeleteBulk(context.Background(), []string{"a", "b"})
+			},
+			wantMethod: "DELETE",
+			wantPaths:  []string{"/channels/a", "/channels/b"},
+		},
+		{
+			name: "delete tokens",
+			fn: func(c clientDoer) ([]BulkResult, error) {
+				return ClientCategoryChannelsTokens{c: c}.DeleteBulk(context.Background(), []string{"a", "b"})
+			},
+			wantMethod: "DELETE",
+			wantPaths:  []string{"/channels/tokens/a", "/channels/tokens/b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var paths []string
+			c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+				assert.Equal(t, tt.wantMethod, a.Method)
+				assert.Equal(t, tt.wantBody, a.Body)
+				mu.Lock()
+				paths = append(paths, a.Path)
+				mu.Unlock()
+				if a.Path == tt.wantPaths[1] {
+					return types.NotFound{Code: "not_found", Message: "not found"}
+				}
+				return nil
+			}}
+			results, err := tt.fn(c)
+			assert.ElementsMatch(t, tt.wantPaths, paths)
+			assert.Equal(t, []BulkResult{{ID: "a"}, {ID: "b", Err: types.NotFound{Code: "not_found", Message: "not found"}}}, results)
+			assert.EqualError(t, err, "1 of 2 items failed (b: not found)")
+		})
+	}
+}
+
+func TestClient_Channels_SubscribeTokens(t *testing.T) {
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		if a.Path == "/channels/channel/subscribers/b" {
+			return types.NotFound{Code: "not_found", Message: "not found"}
+		}
+		return nil
+	}}
+	err := ClientCategoryChannels{c: c}.SubscribeTokens(context.Background(), "channel", []string{"a", "b", "c"})
+	assert.Equal(t, &BulkError{
+		Total:  3,
+		Failed: []BulkResult{{ID: "b", Err: types.NotFound{Code: "not_found", Message: "not found"}}},
+	}, err)
+}
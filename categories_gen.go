@@ -48,6 +48,13 @@ type ClientCategoryIgniteContainers struct {
 	c clientDoer
 }
 
+// ClientCategoryIgniteBuilds is an auto-generated struct which is used to allow for simple categorisation of the APIs.
+// It is public since it may be desired to store a reference to this somewhere, however, do NOT create a instance of this
+// directly. Instead, call NewClient and then go to the field Ignite.Builds.
+type ClientCategoryIgniteBuilds struct {
+	c clientDoer
+}
+
 // ClientCategoryIgnite is an auto-generated struct which is used to allow for simple categorisation of the APIs.
 // It is public since it may be desired to store a reference to this somewhere, however, do NOT create a instance of this
 // directly. Instead, call NewClient and then go to the field Ignite.
@@ -57,6 +64,7 @@ type ClientCategoryIgnite struct {
 	Gateways    *ClientCategoryIgniteGateways
 	Deployments *ClientCategoryIgniteDeployments
 	Containers  *ClientCategoryIgniteContainers
+	Builds      *ClientCategoryIgniteBuilds
 }
 
 func newIgnite(c clientDoer) *ClientCategoryIgnite {
@@ -65,6 +73,7 @@ func newIgnite(c clientDoer) *ClientCategoryIgnite {
 		Gateways:    &ClientCategoryIgniteGateways{c},
 		Deployments: &ClientCategoryIgniteDeployments{c},
 		Containers:  &ClientCategoryIgniteContainers{c},
+		Builds:      &ClientCategoryIgniteBuilds{c},
 	}
 }
 
@@ -2,10 +2,11 @@ package hop
 
 import (
 	"context"
+	"errors"
 	"net/url"
+	"time"
 
 	"go.hop.io/sdk/types"
-	"golang.org/x/sync/errgroup"
 )
 
 // Create is used to create a channel. The channelType argument should be the type of channel that you want to create, state
@@ -92,16 +93,11 @@ func (c ClientCategoryChannels) RemoveToken(ctx context.Context, channelId, toke
 	}, opts)
 }
 
-// SubscribeTokens is used to subscribe many tokens to a channel.
+// SubscribeTokens is used to subscribe many tokens to a channel. Every token is attempted, and a *BulkError listing the
+// tokens which failed is returned if any of them failed. Use SubscribeTokensBulk to get the result of each token.
 func (c ClientCategoryChannels) SubscribeTokens(ctx context.Context, channelId string, tokens []string, opts ...ClientOption) error {
-	eg := errgroup.Group{}
-	for _, v := range tokens {
-		token := v
-		eg.Go(func() error {
-			return c.SubscribeToken(ctx, channelId, token, opts...)
-		})
-	}
-	return eg.Wait()
+	_, err := c.SubscribeTokensBulk(ctx, channelId, tokens, opts...)
+	return err
 }
 
 // SetState is used to set the state of a channel.
@@ -114,7 +110,10 @@ func (c ClientCategoryChannels) SetState(ctx context.Context, id string, state m
 	}, opts)
 }
 
-// PatchState is used to patch the state of a channel.
+// PatchState is used to patch the state of a channel. The patch is applied with JSON merge patch semantics (RFC 7396):
+// keys in the patch replace the same keys in the state, nested objects are merged recursively, keys set to nil are
+// removed, and keys not in the patch are left as they are. Arrays are replaced rather than merged. Use SetState to
+// replace the whole state, or UpdateState to make changes based on the current state.
 func (c ClientCategoryChannels) PatchState(ctx context.Context, id string, state map[string]any, opts ...ClientOption) error {
 	return c.c.do(ctx, ClientArgs{
 		Method:    "PATCH",
@@ -134,6 +133,50 @@ func (c ClientCategoryChannels) PublishMessage(ctx context.Context, channelId, e
 	}, opts)
 }
 
+// GetMessages returns a paginator to get the messages which were published to a channel, oldest first. If since is
+// not zero, only messages published after it are returned. If afterId is not blank, only messages published after the
+// message with that ID are returned.
+func (c ClientCategoryChannels) GetMessages(channelId string, since time.Time, afterId string) *Paginator[*types.ChannelMessage] {
+	query := map[string]string{}
+	if !since.IsZero() {
+		query["since"] = string(types.TimestampFromTime(since))
+	}
+	if afterId != "" {
+		query["after"] = afterId
+	}
+	return &Paginator[*types.ChannelMessage]{
+		c:         c.c,
+		total:     -1,
+		path:      "/channels/" + url.PathEscape(channelId) + "/messages",
+		resultKey: "messages",
+		sortBy:    "created_at",
+		query:     query,
+	}
+}
+
+// MessageHistory returns a function which gets every message published to a channel since the time or message ID
+// specified, oldest first. This can be used with leap.WithReplay to replay messages when subscribing:
+//
+//	ch, err := leapClient.Subscribe("channel_id", leap.WithReplay(lastSeen, "", c.Channels.MessageHistory()))
+func (c ClientCategoryChannels) MessageHistory(
+	opts ...ClientOption,
+) func(ctx context.Context, channelId string, since time.Time, afterId string) ([]*types.ChannelMessage, error) {
+	return func(ctx context.Context, channelId string, since time.Time, afterId string) ([]*types.ChannelMessage, error) {
+		p := c.GetMessages(channelId, since, afterId)
+		var messages []*types.ChannelMessage
+		for {
+			a, err := p.Next(ctx, opts...)
+			if err == types.StopIteration {
+				return messages, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, a...)
+		}
+	}
+}
+
 // Delete is used to delete a channel.
 func (c ClientCategoryChannels) Delete(ctx context.Context, id string, opts ...ClientOption) error {
 	return c.c.do(ctx, ClientArgs{
@@ -168,20 +211,16 @@ func (t ClientCategoryChannelsTokens) Delete(ctx context.Context, id string, opt
 	}, opts)
 }
 
-// Create is used to create a new channel token. State is the map of the state of the token (this can be nil), and
-// projectId is the project ID to associate the token with (this can be empty unless it is bearer or PAT auth).
-func (t ClientCategoryChannelsTokens) Create(ctx context.Context, state map[string]any, opts ...ClientOption) (*types.ChannelToken, error) {
+// Creates a channel token with the body specified.
+func (t ClientCategoryChannelsTokens) create(ctx context.Context, body any, opts []ClientOption) (*types.ChannelToken, error) {
 	if t.c.getProjectId(opts) == "" && t.c.getTokenType() != "ptk" {
 		return nil, types.InvalidToken("project ID must be specified when creating a channel token with bearer or PAT auth")
 	}
-	if state == nil {
-		state = map[string]any{}
-	}
 	var ct types.ChannelToken
 	err := t.c.do(ctx, ClientArgs{
 		Method:    "POST",
 		Path:      "/channels/tokens",
-		Body:      map[string]any{"state": state},
+		Body:      body,
 		ResultKey: "token",
 		Result:    &ct,
 		Ignore404: false,
@@ -192,6 +231,33 @@ func (t ClientCategoryChannelsTokens) Create(ctx context.Context, state map[stri
 	return &ct, nil
 }
 
+// Create is used to create a new channel token. State is the map of the state of the token (this can be nil), and
+// projectId is the project ID to associate the token with (this can be empty unless it is bearer or PAT auth).
+func (t ClientCategoryChannelsTokens) Create(ctx context.Context, state map[string]any, opts ...ClientOption) (*types.ChannelToken, error) {
+	if state == nil {
+		state = map[string]any{}
+	}
+	return t.create(ctx, map[string]any{"state": state}, opts)
+}
+
+// CreateWithOptions is used to create a new channel token with the options specified, such as how long the token is
+// valid for. The expiry must be 0 (the token does not expire) or at least a second.
+func (t ClientCategoryChannelsTokens) CreateWithOptions(
+	ctx context.Context, createOpts types.ChannelTokenCreationOptions, opts ...ClientOption,
+) (*types.ChannelToken, error) {
+	if createOpts.ExpiresIn < 0 {
+		return nil, errors.New("token expiry must not be negative")
+	}
+	if createOpts.ExpiresIn != 0 && time.Duration(createOpts.ExpiresIn) < time.Second {
+		// The expiry is sent in seconds, so this would be rounded down to 0 and the token would never expire.
+		return nil, errors.New("token expiry must be at least 1 second")
+	}
+	if createOpts.State == nil {
+		createOpts.State = map[string]any{}
+	}
+	return t.create(ctx, createOpts, opts)
+}
+
 // SetState is used to set the state of a channel token.
 func (t ClientCategoryChannelsTokens) SetState(ctx context.Context, id string, state map[string]any, opts ...ClientOption) error {
 	return t.c.do(ctx, ClientArgs{
@@ -254,3 +320,70 @@ func (t ClientCategoryChannelsTokens) GetAll(ctx context.Context, opts ...Client
 	}
 	return a, nil
 }
+
+// GetAllPaginated returns a paginator to get all the tokens a page at a time. This is useful for projects with a
+// large number of tokens.
+func (t ClientCategoryChannelsTokens) GetAllPaginated() *Paginator[*types.ChannelToken] {
+	return &Paginator[*types.ChannelToken]{
+		c:         t.c,
+		total:     -1,
+		path:      "/channels/tokens",
+		resultKey: "tokens",
+	}
+}
+
+// GetSubscriptions is used to get the channels which a token is subscribed to.
+func (t ClientCategoryChannelsTokens) GetSubscriptions(ctx context.Context, id string, opts ...ClientOption) ([]*types.Channel, error) {
+	var a []*types.Channel
+	err := t.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      "/channels/tokens/" + url.PathEscape(id) + "/channels",
+		ResultKey: "channels",
+		Result:    &a,
+		Ignore404: false,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Rotate is used to replace a token with a new one. The new token is created with the same state and the expiry
+// specified (0 means that it does not expire, otherwise it must be at least a second), it is subscribed to every
+// channel the old token is subscribed to, and then the old token is deleted. If the subscriptions cannot be migrated,
+// the new token is deleted and the old token is left as it was. If only deleting the old token fails, the new token is
+// returned alongside the error.
+func (t ClientCategoryChannelsTokens) Rotate(
+	ctx context.Context, id string, expiresIn time.Duration, opts ...ClientOption,
+) (*types.ChannelToken, error) {
+	old, err := t.Get(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := t.GetSubscriptions(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := t.CreateWithOptions(ctx, types.ChannelTokenCreationOptions{
+		State:     old.State,
+		ExpiresIn: types.Seconds(expiresIn),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	channelIds := make([]string, len(channels))
+	for i, v := range channels {
+		channelIds[i] = v.ID
+	}
+	ch := ClientCategoryChannels{c: t.c}
+	if _, err = runBulk(ctx, channelIds, opts, func(channelId string) error {
+		return ch.SubscribeToken(ctx, channelId, ct.ID, opts...)
+	}); err != nil {
+		_ = t.Delete(ctx, ct.ID, opts...)
+		return nil, err
+	}
+
+	// The new token is returned even if this fails, since it is fully set up.
+	return ct, t.Delete(ctx, id, opts...)
+}
@@ -1,9 +1,14 @@
 package hop
 
 import (
+	"context"
+	"encoding/json"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.hop.io/sdk/types"
 )
 
@@ -138,6 +143,46 @@ func TestClient_Channels_PublishMessage(t *testing.T) {
 		nil)
 }
 
+func TestClient_Channels_GetMessages(t *testing.T) {
+	c := &mockClientDoer{}
+	since := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	res := (&ClientCategoryChannels{c: c}).GetMessages("test test", since, "message_1")
+	assert.Equal(t, res, &Paginator[*types.ChannelMessage]{
+		c:         c,
+		total:     -1,
+		path:      "/channels/test%20test/messages",
+		resultKey: "messages",
+		sortBy:    "created_at",
+		query:     map[string]string{"since": "2022-01-01T00:00:00Z", "after": "message_1"},
+	})
+	assert.Equal(t, map[string]string{}, (&ClientCategoryChannels{c: c}).GetMessages("test", time.Time{}, "").query)
+}
+
+func TestClient_Channels_MessageHistory(t *testing.T) {
+	pages := []string{
+		`{"messages":[{"id":"message_2","e":"hello"},{"id":"message_3","e":"world"}],"total_count":3}`,
+		`{"messages":[{"id":"message_4","e":"!"}],"total_count":3}`,
+	}
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, opts []ClientOption) error {
+		assert.Equal(t, "/channels/test/messages", a.Path)
+		assert.Equal(t, "message_1", a.Query["after"])
+		assert.Equal(t, []ClientOption{WithProjectID("test123")}, opts)
+		var m map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(pages[0]), &m))
+		pages = pages[1:]
+		setResult(a, m)
+		return nil
+	}}
+	history := ClientCategoryChannels{c: c}.MessageHistory(WithProjectID("test123"))
+	messages, err := history(context.Background(), "test", time.Time{}, "message_1")
+	require.NoError(t, err)
+	assert.Equal(t, []*types.ChannelMessage{
+		{ID: "message_2", EventName: "hello"},
+		{ID: "message_3", EventName: "world"},
+		{ID: "message_4", EventName: "!"},
+	}, messages)
+}
+
 func TestClient_Channels_Delete(t *testing.T) {
 	c := &mockClientDoer{
 		t:              t,
@@ -205,6 +250,141 @@ func TestClient_Channels_Tokens_Create(t *testing.T) {
 		&types.ChannelToken{ID: "hello"})
 }
 
+func TestClient_Channels_Tokens_CreateWithOptions(t *testing.T) {
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "POST",
+		wantPath:       "/channels/tokens",
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		wantResultKey:  "token",
+		wantIgnore404:  false,
+		wantBody: types.ChannelTokenCreationOptions{
+			State:     map[string]any{},
+			ExpiresIn: types.SecondsFromInt(3600),
+		},
+		tokenType: "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryChannelsTokens{c: c},
+		"CreateWithOptions",
+		[]any{types.ChannelTokenCreationOptions{ExpiresIn: types.SecondsFromInt(3600)}, WithProjectID("test123")},
+		&types.ChannelToken{ID: "hello"})
+
+	_, err := ClientCategoryChannelsTokens{c: c}.CreateWithOptions(context.Background(),
+		types.ChannelTokenCreationOptions{ExpiresIn: -1}, WithProjectID("test123"))
+	assert.EqualError(t, err, "token expiry must not be negative")
+
+	// This would be sent as 0 seconds, which would make a token that never expires.
+	_, err = ClientCategoryChannelsTokens{c: c}.CreateWithOptions(context.Background(),
+		types.ChannelTokenCreationOptions{ExpiresIn: types.Seconds(300 * time.Millisecond)}, WithProjectID("test123"))
+	assert.EqualError(t, err, "token expiry must be at least 1 second")
+}
+
+func TestClient_Channels_Tokens_GetSubscriptions(t *testing.T) {
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "GET",
+		wantPath:       "/channels/tokens/test%20test123/channels",
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		wantResultKey:  "channels",
+		wantIgnore404:  false,
+		tokenType:      "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryChannelsTokens{c: c},
+		"GetSubscriptions",
+		[]any{"test test123", WithProjectID("test123")},
+		[]*types.Channel{{ChannelPartial: types.ChannelPartial{ID: "hello"}}})
+}
+
+func TestClient_Channels_Tokens_Rotate(t *testing.T) {
+	tests := []struct {
+		name string
+
+		subscribeErr error
+		wantCalls    []string
+		wantErr      error
+	}{
+		{
+			name: "success",
+			wantCalls: []string{
+				"GET /channels/tokens/old",
+				"GET /channels/tokens/old/channels",
+				"POST /channels/tokens",
+				"PUT /channels/a/subscribers/new",
+				"PUT /channels/b/subscribers/new",
+				"DELETE /channels/tokens/old",
+			},
+		},
+		{
+			name:         "subscribe error",
+			subscribeErr: types.ServerError("boom"),
+			wantCalls: []string{
+				"GET /channels/tokens/old",
+				"GET /channels/tokens/old/channels",
+				"POST /channels/tokens",
+				"PUT /channels/a/subscribers/new",
+				"PUT /channels/b/subscribers/new",
+				"DELETE /channels/tokens/new",
+			},
+			wantErr: &BulkError{Total: 2, Failed: []BulkResult{{ID: "b", Err: types.ServerError("boom")}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var calls []string
+			c := &funcClientDoer{tokenType: "ptk", fn: func(a ClientArgs, _ []ClientOption) error {
+				mu.Lock()
+				calls = append(calls, a.Method+" "+a.Path)
+				mu.Unlock()
+				switch a.Method + " " + a.Path {
+				case "GET /channels/tokens/old":
+					setResult(a, types.ChannelToken{ID: "old", State: map[string]any{"user": "1"}})
+				case "GET /channels/tokens/old/channels":
+					setResult(a, []*types.Channel{
+						{ChannelPartial: types.ChannelPartial{ID: "a"}},
+						{ChannelPartial: types.ChannelPartial{ID: "b"}},
+					})
+				case "POST /channels/tokens":
+					assert.Equal(t, types.ChannelTokenCreationOptions{
+						State:     map[string]any{"user": "1"},
+						ExpiresIn: types.SecondsFromInt(60),
+					}, a.Body)
+					setResult(a, types.ChannelToken{ID: "new", State: map[string]any{"user": "1"}})
+				case "PUT /channels/b/subscribers/new":
+					return tt.subscribeErr
+				}
+				return nil
+			}}
+			ct, err := ClientCategoryChannelsTokens{c: c}.Rotate(context.Background(), "old", time.Minute)
+			assert.ElementsMatch(t, tt.wantCalls, calls)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				assert.Equal(t, "new", ct.ID)
+			} else {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, ct)
+			}
+		})
+	}
+}
+
+func TestClient_Channels_Tokens_Rotate_SubSecond(t *testing.T) {
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		if a.Method != "GET" {
+			t.Fatalf("unexpected %s request to %s", a.Method, a.Path)
+		}
+		if a.ResultKey == "token" {
+			setResult(a, types.ChannelToken{ID: "old"})
+		}
+		return nil
+	}}
+	ct, err := ClientCategoryChannelsTokens{c: c}.Rotate(context.Background(), "old", 500*time.Millisecond)
+	assert.EqualError(t, err, "token expiry must be at least 1 second")
+	assert.Nil(t, ct)
+}
+
 func TestClient_Channels_Tokens_SetState(t *testing.T) {
 	c := &mockClientDoer{
 		t:              t,
@@ -272,3 +452,14 @@ func TestClient_Channels_Tokens_GetAll(t *testing.T) {
 		[]any{WithProjectID("test123")},
 		[]*types.ChannelToken{{ID: "hello"}})
 }
+
+func TestClient_Channels_Tokens_GetAllPaginated(t *testing.T) {
+	c := &mockClientDoer{}
+	res := (&ClientCategoryChannelsTokens{c: c}).GetAllPaginated()
+	assert.Equal(t, res, &Paginator[*types.ChannelToken]{
+		c:         c,
+		total:     -1,
+		path:      "/channels/tokens",
+		resultKey: "tokens",
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/gorilla/websocket"
 	"go.hop.io/sdk/types"
 	"moul.io/http2curl"
 )
@@ -98,6 +99,15 @@ func (c *Client) SetAPIBase(apiBase string) *Client {
 // PlainText is a special case for a body that should be sent as text/plain.
 type PlainText []byte
 
+// ReaderBody is a special case for a body that should be streamed from a reader rather than buffered into memory.
+type ReaderBody struct {
+	// ContentType is the content type of the body.
+	ContentType string
+
+	// Reader is the reader which the body is streamed from.
+	Reader io.Reader
+}
+
 // ClientArgs is used to define the arguments from the function to the client.
 type ClientArgs struct {
 	// Method is used to define the request method.
@@ -112,7 +122,7 @@ type ClientArgs struct {
 	// Query is any HTTP query parameters that should be sent.
 	Query map[string]string
 
-	// Body is the body to send. This should be json marshalled except for the PlainText type.
+	// Body is the body to send. This should be json marshalled except for the PlainText and ReaderBody types.
 	Body any
 
 	// Result is a pointer to where the result should be unmarshalled. Note that if this is nil, the
@@ -127,6 +137,13 @@ type ClientArgs struct {
 	// This is used for the filesystem since we need to pass around a context that constantly pulls more
 	// information in some cases.
 	PassRequest func(r *http.Response)
+
+	// Headers is used to define any extra headers which should be sent with the request.
+	Headers map[string]string
+
+	// ResponseHeaders is used to define a header map which the response headers are copied into if the request is
+	// successful. This can be nil.
+	ResponseHeaders http.Header
 }
 
 type responseBody struct {
@@ -175,60 +192,32 @@ func (c *Client) getProjectId(opts []ClientOption) string {
 	return projectId
 }
 
-func (c *Client) setRequestHeaders(req *http.Request, r io.Reader, textPlain bool) {
+func (c *Client) setRequestHeaders(req *http.Request, r io.Reader, contentType string, headers map[string]string) {
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 	req.Header.Set("Authorization", c.authorization)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", userAgent)
 	if r != nil {
-		// This means we have a body of some description. What content type should we use?
-		if textPlain {
-			req.Header.Set("Content-Type", "text/plain")
-		} else {
-			req.Header.Set("Content-Type", "application/json")
-		}
+		// This means we have a body of some description.
+		req.Header.Set("Content-Type", contentType)
 	}
 }
 
-// Does the specified HTTP request.
-func (c *Client) do(ctx context.Context, a ClientArgs, clientOpts []ClientOption) error { //nolint:funlen,gocognit,gocyclo,cyclop
-	// Transform the client options.
-	processedOpts := c.processOpts(clientOpts)
-
-	// Handle client overrides.
-	if processedOpts.CustomHandler != nil {
-		return processedOpts.CustomHandler(ctx, a, processedOpts)
-	}
-
-	// Handle getting the body bytes.
-	var body []byte
-	textPlain := false
-	if a.Method != "GET" && a.Body != nil {
-		switch x := a.Body.(type) {
-		case PlainText:
-			textPlain = true
-			body = x
-		case []byte:
-			body = x
-		default:
-			bodyBytes, err := json.Marshal(a.Body)
-			if err != nil {
-				return err
-			}
-			body = bodyBytes
-		}
-	}
-
+// Builds the URL for the path and query specified.
+func (c *Client) buildURL(path string, query map[string]string, processedOpts ProcessedClientOpts) string {
 	// Add project ID to the query if it is specified.
 	if processedOpts.ProjectID != "" {
-		if a.Query == nil {
-			a.Query = map[string]string{}
+		if query == nil {
+			query = map[string]string{}
 		}
-		a.Query["project"] = processedOpts.ProjectID
+		query["project"] = processedOpts.ProjectID
 	}
 
-	// Create the request.
+	// Create the query string.
 	suffix := ""
-	if a.Query != nil {
+	if query != nil {
 		suffix = "?"
 		first := true
 
@@ -244,19 +233,19 @@ func (c *Client) do(ctx context.Context, a ClientArgs, clientOpts []ClientOption
 
 		if c.isTest {
 			// Order all the keys for unit testing reasons.
-			keys := make([]string, len(a.Query))
+			keys := make([]string, len(query))
 			i := 0
-			for k := range a.Query {
+			for k := range query {
 				keys[i] = k
 				i++
 			}
 			sort.Strings(keys)
 			for _, k := range keys {
-				addChunk(k, a.Query[k])
+				addChunk(k, query[k])
 			}
 		} else {
 			// Just proceed as usual.
-			for k, v := range a.Query {
+			for k, v := range query {
 				addChunk(k, v)
 			}
 		}
@@ -267,18 +256,58 @@ func (c *Client) do(ctx context.Context, a ClientArgs, clientOpts []ClientOption
 		apiBase = DefaultAPIBase
 	}
 
+	return apiBase + path + suffix
+}
+
+// Does the specified HTTP request.
+func (c *Client) do(ctx context.Context, a ClientArgs, clientOpts []ClientOption) error { //nolint:funlen,gocognit,gocyclo,cyclop
+	// Transform the client options.
+	processedOpts := c.processOpts(clientOpts)
+
+	// Handle client overrides.
+	if processedOpts.CustomHandler != nil {
+		return processedOpts.CustomHandler(ctx, a, processedOpts)
+	}
+
+	// Handle getting the body bytes.
+	var body []byte
+	var bodyStream io.Reader
+	contentType := "application/json"
+	if a.Method != "GET" && a.Body != nil {
+		switch x := a.Body.(type) {
+		case PlainText:
+			contentType = "text/plain"
+			body = x
+		case ReaderBody:
+			contentType = x.ContentType
+			bodyStream = x.Reader
+		case []byte:
+			body = x
+		default:
+			bodyBytes, err := json.Marshal(a.Body)
+			if err != nil {
+				return err
+			}
+			body = bodyBytes
+		}
+	}
+
+	// Create the request URL.
+	reqUrl := c.buildURL(a.Path, a.Query, processedOpts)
+
 	if len(processedOpts.CurlDebugWriters) != 0 {
 		// If curl debugging is on, we make the request structure twice. This is because NewRequestWithContext
-		// takes a reader, and we do not want to pollute that.
+		// takes a reader, and we do not want to pollute that. Streamed bodies cannot be read twice, so they are
+		// left out of the curl command.
 		var r io.Reader
 		if body != nil {
 			r = bytes.NewReader(body)
 		}
-		curlReq, err := http.NewRequest(a.Method, apiBase+a.Path+suffix, r) //nolint:noctx // Built for curl handler.
+		curlReq, err := http.NewRequest(a.Method, reqUrl, r) //nolint:noctx // Built for curl handler.
 		if err != nil {
 			return err
 		}
-		c.setRequestHeaders(curlReq, r, textPlain)
+		c.setRequestHeaders(curlReq, r, contentType, a.Headers)
 
 		// Convert the request to a curl command.
 		var curl *http2curl.CurlCommand
@@ -299,12 +328,14 @@ func (c *Client) do(ctx context.Context, a ClientArgs, clientOpts []ClientOption
 	var r io.Reader
 	if body != nil {
 		r = bytes.NewReader(body)
+	} else if bodyStream != nil {
+		r = bodyStream
 	}
-	req, err := http.NewRequestWithContext(ctx, a.Method, apiBase+a.Path+suffix, r)
+	req, err := http.NewRequestWithContext(ctx, a.Method, reqUrl, r)
 	if err != nil {
 		return err
 	}
-	c.setRequestHeaders(req, r, textPlain)
+	c.setRequestHeaders(req, r, contentType, a.Headers)
 
 	// Do the request.
 	res, err := c.httpClient.Do(req)
@@ -328,6 +359,13 @@ func (c *Client) do(ctx context.Context, a ClientArgs, clientOpts []ClientOption
 		}
 	}
 
+	// Copy the response headers if they were requested.
+	if a.ResponseHeaders != nil {
+		for k, v := range res.Header {
+			a.ResponseHeaders[k] = v
+		}
+	}
+
 	// Handle if we should pass off the request.
 	if a.PassRequest != nil {
 		a.PassRequest(res)
@@ -372,10 +410,48 @@ func (c *Client) do(ctx context.Context, a ClientArgs, clientOpts []ClientOption
 	return nil
 }
 
+// Dials a websocket connection to the path specified.
+func (c *Client) dialWebsocket(
+	ctx context.Context, path string, query map[string]string, clientOpts []ClientOption,
+) (*websocket.Conn, error) {
+	// Transform the client options.
+	processedOpts := c.processOpts(clientOpts)
+	if processedOpts.CustomHandler != nil {
+		return nil, errors.New("websocket connections are not supported with a custom handler")
+	}
+
+	// Create the URL with the websocket scheme.
+	reqUrl := c.buildURL(path, query, processedOpts)
+	switch {
+	case strings.HasPrefix(reqUrl, "https://"):
+		reqUrl = "wss://" + strings.TrimPrefix(reqUrl, "https://")
+	case strings.HasPrefix(reqUrl, "http://"):
+		reqUrl = "ws://" + strings.TrimPrefix(reqUrl, "http://")
+	}
+	h := http.Header{}
+	h.Set("Authorization", c.authorization)
+	h.Set("User-Agent", userAgent)
+
+	// Do the handshake.
+	conn, res, err := websocket.DefaultDialer.DialContext(ctx, reqUrl, h)
+	if res != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		if res != nil && res.StatusCode >= 400 && 599 >= res.StatusCode {
+			// Handle the error in the same way as any other request.
+			return nil, handleErrors(res)
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
 type clientDoer interface {
 	do(ctx context.Context, a ClientArgs, opts []ClientOption) error
 	getTokenType() string
 	getProjectId([]ClientOption) string
+	dialWebsocket(ctx context.Context, path string, query map[string]string, opts []ClientOption) (*websocket.Conn, error)
 }
 
 // Paginator is used to create a way to access paginated API routes.
@@ -470,7 +546,7 @@ func (p *Paginator[T]) Next(ctx context.Context, opts ...ClientOption) ([]T, err
 // ForChunk is basically the shorthand for calling a function everytime there is a new result. Any errors are passed to
 // the root error result.
 func (p *Paginator[T]) ForChunk(ctx context.Context, f func([]T) error, opts ...ClientOption) error {
-	for a, err := p.Next(ctx, opts...); err != types.StopIteration; a, err = p.Next(ctx) {
+	for a, err := p.Next(ctx, opts...); err != types.StopIteration; a, err = p.Next(ctx, opts...) {
 		if err != nil {
 			return err
 		}
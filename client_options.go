@@ -4,6 +4,9 @@ import (
 	"context"
 	"io"
 	"strings"
+
+	"go.hop.io/sdk/leap"
+	"go.hop.io/sdk/types"
 )
 
 // ClientOption is used to define am option that the client will consume when it is ran.
@@ -55,6 +58,41 @@ func WithCurlDebugWriter(w io.Writer) ClientOption {
 	return curlWriterOption{w: w}
 }
 
+type concurrencyOption struct {
+	baseClientOption
+
+	n int
+}
+
+// WithConcurrency is used to set how many requests a bulk operation makes at once. This only applies to bulk
+// operations and should be passed to them directly. By default, this is DefaultBulkConcurrency.
+func WithConcurrency(n int) ClientOption {
+	return concurrencyOption{n: n}
+}
+
+// Defines the parts of the Leap client used to wait for events. This is implemented by *leap.Client.
+type leapEventSource interface {
+	AddChannelEventListener(fn func(types.LeapChannelEvent)) func()
+	State() types.LeapStateInfo
+}
+
+type leapClientOption struct {
+	baseClientOption
+
+	c leapEventSource
+}
+
+// WithLeapClient is used to set the Leap client used by functions which wait for events, such as
+// Pipe.Rooms.WaitLive. The client should be connected with a project scoped token. Without this, the functions poll
+// the API instead.
+func WithLeapClient(c *leap.Client) ClientOption {
+	if c == nil {
+		// Do not wrap a nil pointer in the interface, since it would not compare equal to nil.
+		return leapClientOption{}
+	}
+	return leapClientOption{c: c}
+}
+
 type customHandlerOption struct {
 	baseClientOption
 
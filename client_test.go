@@ -274,8 +274,9 @@ type mockHttpRoundTripper struct {
 	wantUrl     string
 	wantBody    string
 
-	returnsStatus int
-	returnsBody   string
+	returnsStatus  int
+	returnsBody    string
+	returnsHeaders http.Header
 }
 
 func (h mockHttpRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -297,6 +298,7 @@ func (h mockHttpRoundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 
 	return &http.Response{
 		StatusCode: h.returnsStatus,
+		Header:     h.returnsHeaders,
 		Body:       io.NopCloser(strings.NewReader(h.returnsBody)),
 	}, nil
 }
@@ -324,16 +326,18 @@ func TestClient_do(t *testing.T) {
 		wantUrl     string
 		wantBody    string
 
-		returnsStatus int
-		returnsBody   string
-		returnsError  error
-		returnsCurl   string
+		returnsStatus  int
+		returnsBody    string
+		returnsHeaders http.Header
+		returnsError   error
+		returnsCurl    string
 
 		baseUrl      string
 		method       string
 		path         string
 		resultKey    string
 		query        map[string]string
+		headers      map[string]string
 		body         any
 		ignore404    bool
 		clientOpts   []ClientOption
@@ -691,6 +695,51 @@ func TestClient_do(t *testing.T) {
 			path:          "/test",
 			body:          PlainText("hello world"),
 		},
+		{
+			name: "reader body post request",
+			wantHeaders: http.Header{
+				"Accept":        {"application/json"},
+				"Authorization": {"testing"},
+				"Content-Type":  {"application/octet-stream"},
+				"User-Agent":    {userAgent},
+			},
+			wantUrl:       "https://api.hop.io/v1/test",
+			wantBody:      "hello world",
+			returnsBody:   `{"data":{"foo":"bar"}}`,
+			returnsStatus: 200,
+			method:        "POST",
+			path:          "/test",
+			body:          ReaderBody{ContentType: "application/octet-stream", Reader: strings.NewReader("hello world")},
+		},
+		{
+			name: "extra headers",
+			wantHeaders: http.Header{
+				"Accept":        {"application/json"},
+				"Authorization": {"testing"},
+				"Range":         {"bytes=0-10"},
+				"User-Agent":    {userAgent},
+			},
+			wantUrl:       "https://api.hop.io/v1/test",
+			returnsBody:   `{"data":{"foo":"bar"}}`,
+			returnsStatus: 200,
+			method:        "GET",
+			path:          "/test",
+			headers:       map[string]string{"Range": "bytes=0-10", "Authorization": "overridden"},
+		},
+		{
+			name: "response headers",
+			wantHeaders: http.Header{
+				"Accept":        {"application/json"},
+				"Authorization": {"testing"},
+				"User-Agent":    {userAgent},
+			},
+			wantUrl:        "https://api.hop.io/v1/test",
+			returnsBody:    `{"data":{"foo":"bar"}}`,
+			returnsStatus:  200,
+			returnsHeaders: http.Header{"Etag": {`"1"`}},
+			method:         "GET",
+			path:           "/test",
+		},
 		{
 			name:         "body marshal error",
 			expectsError: errors.New("marshal fail"),
@@ -723,13 +772,14 @@ func TestClient_do(t *testing.T) {
 			c := &Client{
 				httpClient: &http.Client{
 					Transport: mockHttpRoundTripper{
-						t:             t,
-						err:           tt.returnsError,
-						wantHeaders:   tt.wantHeaders,
-						wantUrl:       tt.wantUrl,
-						wantBody:      tt.wantBody,
-						returnsStatus: tt.returnsStatus,
-						returnsBody:   tt.returnsBody,
+						t:              t,
+						err:            tt.returnsError,
+						wantHeaders:    tt.wantHeaders,
+						wantUrl:        tt.wantUrl,
+						wantBody:       tt.wantBody,
+						returnsStatus:  tt.returnsStatus,
+						returnsBody:    tt.returnsBody,
+						returnsHeaders: tt.returnsHeaders,
 					},
 				},
 				authorization: "testing",
@@ -745,14 +795,20 @@ func TestClient_do(t *testing.T) {
 				// blank responses should be a nil pointer
 				ptr = nil
 			}
+			var resHeaders http.Header
+			if tt.returnsHeaders != nil {
+				resHeaders = http.Header{}
+			}
 			err := c.do(context.Background(), ClientArgs{
-				Method:    tt.method,
-				Path:      tt.path,
-				ResultKey: tt.resultKey,
-				Query:     tt.query,
-				Body:      tt.body,
-				Result:    ptr,
-				Ignore404: tt.ignore404,
+				Method:          tt.method,
+				Path:            tt.path,
+				ResultKey:       tt.resultKey,
+				Query:           tt.query,
+				Headers:         tt.headers,
+				Body:            tt.body,
+				Result:          ptr,
+				Ignore404:       tt.ignore404,
+				ResponseHeaders: resHeaders,
 			}, tt.funcOpts)
 			if tt.expectsError == nil {
 				assert.NoError(t, err)
@@ -767,6 +823,9 @@ func TestClient_do(t *testing.T) {
 				// Check the body is what we expect.
 				assert.Equal(t, map[string]string{"foo": "bar"}, result)
 			}
+			if tt.returnsHeaders != nil {
+				assert.Equal(t, tt.returnsHeaders, resHeaders)
+			}
 			if tt.returnsCurl != "" {
 				// Check the buffer is equal.
 				assert.Equal(t, tt.returnsCurl, strings.ReplaceAll(buf.String(), userAgent, "{user_agent}"))
@@ -1078,4 +1137,21 @@ func TestPaginator_ForChunk(t *testing.T) {
 		})
 		assert.EqualError(t, err, "test error")
 	})
+	t.Run("options on every page", func(t *testing.T) {
+		var pages []string
+		p := &Paginator[string]{
+			c: &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, opts []ClientOption) error {
+				assert.Equal(t, []ClientOption{WithProjectID("test123")}, opts)
+				pages = append(pages, a.Query["page"])
+				setResult(a, rawify(map[string]any{"total_count": 2, "items": []string{"a"}}))
+				return nil
+			}},
+			total:     -1,
+			path:      "/test",
+			resultKey: "items",
+		}
+		err := p.ForChunk(context.Background(), func([]string) error { return nil }, WithProjectID("test123"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, pages)
+	})
 }
@@ -0,0 +1,79 @@
+//go:build linux
+
+// Command hopfs is used to mount an Ignite deployment volume as a local filesystem using FUSE.
+//
+// Usage:
+//
+//	HOP_TOKEN=<token> hopfs [flags] <deployment id> <volume id> <mountpoint>
+//
+// If the write endpoints are not available for the volume, it is mounted read-only. The volume is unmounted when the
+// process is interrupted.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.hop.io/sdk"
+)
+
+func main() {
+	projectId := flag.String("project", "", "the project ID to use, required if the token is not a project token")
+	apiURL := flag.String("api-url", "", "a custom API base URL")
+	attrTTL := flag.Duration("attr-ttl", 5*time.Second, "how long file attributes are cached for")
+	readAhead := flag.Int("read-ahead", 1<<20, "the minimum number of bytes fetched per read")
+	readOnly := flag.Bool("read-only", false, "mount the volume read-only")
+	debug := flag.Bool("debug", false, "log every FUSE request")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: HOP_TOKEN=<token> hopfs [flags] <deployment id> <volume id> <mountpoint>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 3 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var opts []hop.ClientOption
+	if *projectId != "" {
+		opts = append(opts, hop.WithProjectID(*projectId))
+	}
+	if *apiURL != "" {
+		opts = append(opts, hop.WithCustomAPIURL(*apiURL))
+	}
+	c, err := hop.NewClient(os.Getenv("HOP_TOKEN"), opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	vol := c.Ignite.Deployments.WritableVolumeFS(context.Background(), flag.Arg(0), flag.Arg(1))
+	server, err := mount(flag.Arg(2), vol, mountConfig{
+		AttrTTL:   *attrTTL,
+		ReadAhead: *readAhead,
+		ReadOnly:  *readOnly,
+		Debug:     *debug,
+		OnReadOnly: func() {
+			log.Println("the write endpoints are unavailable for this volume, so writes will be rejected")
+		},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("mounted %s/%s at %s", flag.Arg(0), flag.Arg(1), flag.Arg(2))
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		if unmountErr := server.Unmount(); unmountErr != nil {
+			log.Println("failed to unmount:", unmountErr)
+		}
+	}()
+	server.Wait()
+}
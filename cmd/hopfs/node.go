@@ -0,0 +1,628 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	iofs "io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"go.hop.io/sdk"
+	"go.hop.io/sdk/types"
+)
+
+// Defines the options used when mounting a volume.
+type mountConfig struct {
+	// AttrTTL is how long attributes are cached for, both by the kernel and by hopfs.
+	AttrTTL time.Duration
+
+	// ReadAhead is the minimum number of bytes fetched from the API when a file is read.
+	ReadAhead int
+
+	// ReadOnly is used to reject all writes. The mount also becomes read-only if a write finds that the write endpoints
+	// are unavailable for the volume.
+	ReadOnly bool
+
+	// Debug is used to log every FUSE request.
+	Debug bool
+
+	// OnReadOnly is called the first time a write finds that the write endpoints are unavailable. This can be nil.
+	OnReadOnly func()
+}
+
+// Defines a cached attribute lookup.
+type cachedAttr struct {
+	info    iofs.FileInfo
+	expires time.Time
+}
+
+// Defines the state shared by all nodes in a mount.
+type mountState struct {
+	vol *hop.WritableVolumeFS
+	cfg mountConfig
+	uid uint32
+	gid uint32
+
+	// Set to 1 once a write finds that the write endpoints are unavailable.
+	writesUnavailable uint32
+
+	attrsLock sync.Mutex
+	attrs     map[string]cachedAttr
+}
+
+// Returns true if writes should be rejected, either because the volume was mounted read-only or because the write
+// endpoints are unavailable.
+func (s *mountState) readOnly() bool {
+	return s.cfg.ReadOnly || atomic.LoadUint32(&s.writesUnavailable) == 1
+}
+
+// Maps an error from a write to the errno returned to the kernel. If the error shows that the write endpoints are
+// unavailable, the mount switches to read-only so later writes are rejected without a request.
+func (s *mountState) writeErrno(err error) syscall.Errno {
+	if !writesUnsupported(err) {
+		return toErrno(err)
+	}
+	if atomic.CompareAndSwapUint32(&s.writesUnavailable, 0, 1) && s.cfg.OnReadOnly != nil {
+		s.cfg.OnReadOnly()
+	}
+	return syscall.EROFS
+}
+
+// Returns the file info for the path specified, using the cache if the entry has not expired.
+func (s *mountState) stat(name string) (iofs.FileInfo, error) {
+	s.attrsLock.Lock()
+	a, ok := s.attrs[name]
+	s.attrsLock.Unlock()
+	if ok && time.Now().Before(a.expires) {
+		return a.info, nil
+	}
+
+	info, err := iofs.Stat(s.vol, name)
+	if err != nil {
+		return nil, err
+	}
+	s.cache(name, info)
+	return info, nil
+}
+
+// Caches the file info for the path specified.
+func (s *mountState) cache(name string, info iofs.FileInfo) {
+	if s.cfg.AttrTTL <= 0 {
+		return
+	}
+	s.attrsLock.Lock()
+	s.attrs[name] = cachedAttr{info: info, expires: time.Now().Add(s.cfg.AttrTTL)}
+	s.attrsLock.Unlock()
+}
+
+// Removes the path specified and anything below it from the cache.
+func (s *mountState) invalidate(name string) {
+	s.attrsLock.Lock()
+	defer s.attrsLock.Unlock()
+	for k := range s.attrs {
+		if k == name || strings.HasPrefix(k, name+"/") {
+			delete(s.attrs, k)
+		}
+	}
+}
+
+// Fills the attributes from the file info specified.
+func (s *mountState) fillAttr(info iofs.FileInfo, out *fuse.Attr) {
+	out.Mode = uint32(info.Mode().Perm())
+	if info.IsDir() {
+		out.Mode |= fuse.S_IFDIR
+	} else {
+		out.Mode |= fuse.S_IFREG
+	}
+	out.Size = uint64(info.Size())
+	out.Blocks = (out.Size + 511) / 512
+	out.Nlink = 1
+	mtime := info.ModTime()
+	out.SetTimes(&mtime, &mtime, &mtime)
+	out.Owner = fuse.Owner{Uid: s.uid, Gid: s.gid}
+}
+
+// Returns the stable attributes for the file info specified.
+func stableAttr(info iofs.FileInfo) fs.StableAttr {
+	if info.IsDir() {
+		return fs.StableAttr{Mode: fuse.S_IFDIR}
+	}
+	return fs.StableAttr{Mode: fuse.S_IFREG}
+}
+
+// Maps an error from the volume filesystem to the errno returned to the kernel.
+func toErrno(err error) syscall.Errno {
+	if err == nil {
+		return fs.OK
+	}
+
+	var notAuthorized types.NotAuthorized
+	switch {
+	case errors.Is(err, iofs.ErrNotExist):
+		return syscall.ENOENT
+	case errors.Is(err, iofs.ErrExist):
+		return syscall.EEXIST
+	case errors.Is(err, iofs.ErrInvalid):
+		return syscall.EINVAL
+	case errors.Is(err, iofs.ErrPermission), errors.As(err, &notAuthorized):
+		return syscall.EACCES
+	case errors.Is(err, hop.ErrDirectoryNotEmpty):
+		return syscall.ENOTEMPTY
+	case errors.Is(err, hop.ErrNotADirectory):
+		return syscall.ENOTDIR
+	default:
+		return syscall.EIO
+	}
+}
+
+// Defines a file or directory in the volume. The path is worked out from the position of the inode in the tree so
+// renames do not need to update the node.
+type node struct {
+	fs.Inode
+
+	s *mountState
+}
+
+var (
+	_ fs.NodeLookuper  = (*node)(nil)
+	_ fs.NodeGetattrer = (*node)(nil)
+	_ fs.NodeSetattrer = (*node)(nil)
+	_ fs.NodeReaddirer = (*node)(nil)
+	_ fs.NodeOpener    = (*node)(nil)
+	_ fs.NodeCreater   = (*node)(nil)
+	_ fs.NodeMkdirer   = (*node)(nil)
+	_ fs.NodeUnlinker  = (*node)(nil)
+	_ fs.NodeRmdirer   = (*node)(nil)
+	_ fs.NodeRenamer   = (*node)(nil)
+)
+
+// Returns the io/fs path of the node.
+func (n *node) path() string {
+	p := n.Path(nil)
+	if p == "" {
+		return "."
+	}
+	return p
+}
+
+// Returns the io/fs path of a child of the node.
+func (n *node) child(name string) string {
+	return path.Join(n.path(), name)
+}
+
+// Makes a new inode for the child specified and fills the entry.
+func (n *node) newChild(ctx context.Context, info iofs.FileInfo, out *fuse.EntryOut) *fs.Inode {
+	n.s.fillAttr(info, &out.Attr)
+	out.SetEntryTimeout(n.s.cfg.AttrTTL)
+	out.SetAttrTimeout(n.s.cfg.AttrTTL)
+	return n.NewInode(ctx, &node{s: n.s}, stableAttr(info))
+}
+
+// Lookup implements fs.NodeLookuper.
+func (n *node) Lookup(ctx context.Context, name string, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	info, err := n.s.stat(n.child(name))
+	if err != nil {
+		return nil, toErrno(err)
+	}
+	return n.newChild(ctx, info, out), fs.OK
+}
+
+// Getattr implements fs.NodeGetattrer.
+func (n *node) Getattr(ctx context.Context, f fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	if h, ok := f.(*writeHandle); ok {
+		// The buffered contents are the source of truth until they are flushed.
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		out.Mode = fuse.S_IFREG | uint32(h.perm)
+		out.Size = uint64(len(h.data))
+		out.Nlink = 1
+		out.Owner = fuse.Owner{Uid: n.s.uid, Gid: n.s.gid}
+		return fs.OK
+	}
+
+	info, err := n.s.stat(n.path())
+	if err != nil {
+		return toErrno(err)
+	}
+	n.s.fillAttr(info, &out.Attr)
+	out.SetTimeout(n.s.cfg.AttrTTL)
+	return fs.OK
+}
+
+// Setattr implements fs.NodeSetattrer. Only the permissions and the size can be changed. Time changes are ignored
+// since the volume always sets the modification time itself.
+func (n *node) Setattr(ctx context.Context, f fs.FileHandle, in *fuse.SetAttrIn, out *fuse.AttrOut) syscall.Errno {
+	p := n.path()
+	if mode, ok := in.GetMode(); ok {
+		if n.s.readOnly() {
+			return syscall.EROFS
+		}
+		n.s.invalidate(p)
+		if err := n.s.vol.Chmod(p, iofs.FileMode(mode).Perm()); err != nil {
+			return n.s.writeErrno(err)
+		}
+	}
+
+	if size, ok := in.GetSize(); ok {
+		if n.s.readOnly() {
+			return syscall.EROFS
+		}
+		if h, isWrite := f.(*writeHandle); isWrite {
+			h.truncate(int(size))
+		} else if errno := n.truncate(p, int(size)); errno != fs.OK {
+			return errno
+		}
+	}
+
+	return n.Getattr(ctx, f, out)
+}
+
+// Truncates the file specified which is not open for writing.
+func (n *node) truncate(name string, size int) syscall.Errno {
+	info, err := n.s.stat(name)
+	if err != nil {
+		return toErrno(err)
+	}
+	if info.IsDir() {
+		return syscall.EISDIR
+	}
+	var b []byte
+	if size != 0 {
+		if b, err = iofs.ReadFile(n.s.vol, name); err != nil {
+			return toErrno(err)
+		}
+	}
+	b = resize(b, size)
+	n.s.invalidate(name)
+	return n.s.writeErrno(n.s.vol.WriteFile(name, b, info.Mode().Perm()))
+}
+
+// Readdir implements fs.NodeReaddirer. The attributes of each entry are cached so listing a directory does not make
+// a request per entry.
+func (n *node) Readdir(context.Context) (fs.DirStream, syscall.Errno) {
+	p := n.path()
+	entries, err := n.s.vol.ReadDir(p)
+	if err != nil {
+		return nil, toErrno(err)
+	}
+	a := make([]fuse.DirEntry, 0, len(entries))
+	for _, v := range entries {
+		var info iofs.FileInfo
+		if info, err = v.Info(); err != nil {
+			return nil, toErrno(err)
+		}
+		n.s.cache(path.Join(p, v.Name()), info)
+		a = append(a, fuse.DirEntry{Name: v.Name(), Mode: stableAttr(info).Mode})
+	}
+	return fs.NewListDirStream(a), fs.OK
+}
+
+// Open implements fs.NodeOpener.
+func (n *node) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	p := n.path()
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR) == 0 {
+		f, err := n.s.vol.Open(p)
+		if err != nil {
+			return nil, 0, toErrno(err)
+		}
+		return newReadHandle(f, n.s.cfg.ReadAhead), 0, fs.OK
+	}
+
+	if n.s.readOnly() {
+		return nil, 0, syscall.EROFS
+	}
+	info, err := n.s.stat(p)
+	if err != nil {
+		return nil, 0, toErrno(err)
+	}
+	h := &writeHandle{s: n.s, name: p, perm: info.Mode().Perm()}
+	if flags&syscall.O_TRUNC == 0 {
+		if h.data, err = iofs.ReadFile(n.s.vol, p); err != nil {
+			return nil, 0, toErrno(err)
+		}
+	} else {
+		h.dirty = true
+	}
+	return h, fuse.FOPEN_DIRECT_IO, fs.OK
+}
+
+// Create implements fs.NodeCreater. The empty file is uploaded straight away so it shows up in listings before it is
+// flushed.
+func (n *node) Create(
+	ctx context.Context, name string, flags, mode uint32, out *fuse.EntryOut,
+) (*fs.Inode, fs.FileHandle, uint32, syscall.Errno) {
+	if n.s.readOnly() {
+		return nil, nil, 0, syscall.EROFS
+	}
+	p := n.child(name)
+	perm := iofs.FileMode(mode).Perm()
+	n.s.invalidate(p)
+	if err := n.s.vol.WriteFile(p, nil, perm); err != nil {
+		return nil, nil, 0, n.s.writeErrno(err)
+	}
+	info, err := n.s.stat(p)
+	if err != nil {
+		return nil, nil, 0, toErrno(err)
+	}
+	h := &writeHandle{s: n.s, name: p, perm: perm}
+	return n.newChild(ctx, info, out), h, fuse.FOPEN_DIRECT_IO, fs.OK
+}
+
+// Mkdir implements fs.NodeMkdirer.
+func (n *node) Mkdir(ctx context.Context, name string, mode uint32, out *fuse.EntryOut) (*fs.Inode, syscall.Errno) {
+	if n.s.readOnly() {
+		return nil, syscall.EROFS
+	}
+	p := n.child(name)
+	n.s.invalidate(p)
+	if err := n.s.vol.Mkdir(p, iofs.FileMode(mode).Perm()); err != nil {
+		return nil, n.s.writeErrno(err)
+	}
+	info, err := n.s.stat(p)
+	if err != nil {
+		return nil, toErrno(err)
+	}
+	return n.newChild(ctx, info, out), fs.OK
+}
+
+// Removes the child specified. The volume API removes files and empty directories in the same way.
+func (n *node) remove(name string, dir bool) syscall.Errno {
+	if n.s.readOnly() {
+		return syscall.EROFS
+	}
+	p := n.child(name)
+	info, err := n.s.stat(p)
+	if err != nil {
+		return toErrno(err)
+	}
+	if dir && !info.IsDir() {
+		return syscall.ENOTDIR
+	}
+	if !dir && info.IsDir() {
+		return syscall.EISDIR
+	}
+	n.s.invalidate(p)
+	return n.s.writeErrno(n.s.vol.Remove(p))
+}
+
+// Unlink implements fs.NodeUnlinker.
+func (n *node) Unlink(_ context.Context, name string) syscall.Errno {
+	return n.remove(name, false)
+}
+
+// Rmdir implements fs.NodeRmdirer.
+func (n *node) Rmdir(_ context.Context, name string) syscall.Errno {
+	return n.remove(name, true)
+}
+
+// Rename implements fs.NodeRenamer. Exchanging files is not supported by the volume API.
+func (n *node) Rename(_ context.Context, name string, newParent fs.InodeEmbedder, newName string, flags uint32) syscall.Errno {
+	if n.s.readOnly() {
+		return syscall.EROFS
+	}
+	if flags != 0 {
+		return syscall.EINVAL
+	}
+	parent, ok := newParent.(*node)
+	if !ok {
+		return syscall.EXDEV
+	}
+	oldPath := n.child(name)
+	newPath := parent.child(newName)
+	n.s.invalidate(oldPath)
+	n.s.invalidate(newPath)
+	return n.s.writeErrno(n.s.vol.Rename(oldPath, newPath))
+}
+
+// Returns the slice resized to the length specified, padding it with zeros if it is grown.
+func resize(b []byte, size int) []byte {
+	if size <= len(b) {
+		return b[:size]
+	}
+	return append(b, make([]byte, size-len(b))...)
+}
+
+// Defines a handle for a file opened for reading. Reads are served from a buffer which is filled with at least the
+// read-ahead size each time a read falls outside of it.
+type readHandle struct {
+	mu        sync.Mutex
+	f         iofs.File
+	r         io.ReaderAt
+	readAhead int
+	buf       []byte
+	bufOffset int64
+	eof       bool
+}
+
+var (
+	_ fs.FileReader   = (*readHandle)(nil)
+	_ fs.FileReleaser = (*readHandle)(nil)
+)
+
+// Makes a new read handle for the file specified. Volume files always implement io.ReaderAt.
+func newReadHandle(f iofs.File, readAhead int) *readHandle {
+	h := &readHandle{f: f, readAhead: readAhead}
+	if r, ok := f.(io.ReaderAt); ok {
+		h.r = r
+	}
+	return h
+}
+
+// Read implements fs.FileReader.
+func (h *readHandle) Read(_ context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.r == nil {
+		return nil, syscall.EIO
+	}
+
+	end := h.bufOffset + int64(len(h.buf))
+	want := off + int64(len(dest))
+	if off < h.bufOffset || off > end || (want > end && !h.eof) {
+		// The read is not covered by the buffer, so fetch a new chunk.
+		size := len(dest)
+		if size < h.readAhead {
+			size = h.readAhead
+		}
+		buf := make([]byte, size)
+		n, err := h.r.ReadAt(buf, off)
+		if err != nil && err != io.EOF {
+			return nil, toErrno(err)
+		}
+		h.buf = buf[:n]
+		h.bufOffset = off
+		h.eof = err == io.EOF
+		end = off + int64(n)
+	}
+
+	if want > end {
+		want = end
+	}
+	return fuse.ReadResultData(h.buf[off-h.bufOffset : want-h.bufOffset]), fs.OK
+}
+
+// Release implements fs.FileReleaser.
+func (h *readHandle) Release(context.Context) syscall.Errno {
+	return toErrno(h.f.Close())
+}
+
+// Defines a handle for a file opened for writing. The volume API only supports replacing whole files, so the contents
+// are buffered in memory and uploaded when the file is flushed.
+type writeHandle struct {
+	s    *mountState
+	name string
+
+	mu    sync.Mutex
+	perm  iofs.FileMode
+	data  []byte
+	dirty bool
+}
+
+var (
+	_ fs.FileReader   = (*writeHandle)(nil)
+	_ fs.FileWriter   = (*writeHandle)(nil)
+	_ fs.FileFlusher  = (*writeHandle)(nil)
+	_ fs.FileFsyncer  = (*writeHandle)(nil)
+	_ fs.FileReleaser = (*writeHandle)(nil)
+)
+
+// Read implements fs.FileReader.
+func (h *writeHandle) Read(_ context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if off >= int64(len(h.data)) {
+		return fuse.ReadResultData(nil), fs.OK
+	}
+	end := off + int64(len(dest))
+	if end > int64(len(h.data)) {
+		end = int64(len(h.data))
+	}
+	return fuse.ReadResultData(h.data[off:end]), fs.OK
+}
+
+// Write implements fs.FileWriter.
+func (h *writeHandle) Write(_ context.Context, data []byte, off int64) (uint32, syscall.Errno) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	end := int(off) + len(data)
+	if end > len(h.data) {
+		h.data = resize(h.data, end)
+	}
+	copy(h.data[off:], data)
+	h.dirty = true
+	return uint32(len(data)), fs.OK
+}
+
+// Truncates the buffered contents to the size specified.
+func (h *writeHandle) truncate(size int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.data = resize(h.data, size)
+	h.dirty = true
+}
+
+// Uploads the contents if they have changed since the last upload.
+func (h *writeHandle) upload() syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.dirty {
+		return fs.OK
+	}
+	h.s.invalidate(h.name)
+	if err := h.s.vol.WriteFile(h.name, h.data, h.perm); err != nil {
+		return h.s.writeErrno(err)
+	}
+	h.dirty = false
+	return fs.OK
+}
+
+// Flush implements fs.FileFlusher. This is called on every close of the file descriptor.
+func (h *writeHandle) Flush(context.Context) syscall.Errno {
+	return h.upload()
+}
+
+// Fsync implements fs.FileFsyncer.
+func (h *writeHandle) Fsync(context.Context, uint32) syscall.Errno {
+	return h.upload()
+}
+
+// Release implements fs.FileReleaser.
+func (h *writeHandle) Release(context.Context) syscall.Errno {
+	return h.upload()
+}
+
+// The error code returned by the API when the endpoint requested does not exist.
+const endpointNotFoundCode = "endpoint_not_found"
+
+// Returns true if the error from a write shows that the write endpoints are unavailable for the volume. Only a 405 or
+// an explicit endpoint not found error count, since other errors such as a missing parent directory or a permission
+// error only apply to that write and should not make the whole mount read-only.
+func writesUnsupported(err error) bool {
+	var notFound types.NotFound
+	if errors.As(err, &notFound) {
+		return notFound.Code == endpointNotFoundCode
+	}
+	var unknown types.UnknownServerError
+	if errors.As(err, &unknown) {
+		return unknown.StatusCode == 405 || unknown.Code == endpointNotFoundCode
+	}
+	return false
+}
+
+// Mounts the volume at the directory specified.
+func mount(dir string, vol *hop.WritableVolumeFS, cfg mountConfig) (*fuse.Server, error) {
+	s := &mountState{
+		vol:   vol,
+		cfg:   cfg,
+		uid:   uint32(os.Getuid()),
+		gid:   uint32(os.Getgid()),
+		attrs: map[string]cachedAttr{},
+	}
+	options := []string{"default_permissions"}
+	if cfg.ReadOnly {
+		options = append(options, "ro")
+	}
+	server, err := fs.Mount(dir, &node{s: s}, &fs.Options{
+		EntryTimeout: &cfg.AttrTTL,
+		AttrTimeout:  &cfg.AttrTTL,
+		MountOptions: fuse.MountOptions{
+			FsName:       "hopfs",
+			Name:         "hopfs",
+			Options:      options,
+			DirectMount:  true,
+			MaxReadAhead: cfg.ReadAhead,
+			Debug:        cfg.Debug,
+		},
+	})
+	return server, err
+}
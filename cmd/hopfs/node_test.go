@@ -0,0 +1,213 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	iofs "io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk"
+	"go.hop.io/sdk/internal/volumetest"
+	"go.hop.io/sdk/types"
+)
+
+// Returns a writable filesystem for the fake volume. Streamed reads are counted in the pointer returned.
+func newTestVolume(t *testing.T, s *volumetest.Server) (*hop.WritableVolumeFS, *int32) {
+	t.Helper()
+	var streams int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("stream") == "true" {
+			atomic.AddInt32(&streams, 1)
+		}
+		s.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	c, err := hop.NewClient("pat_test", hop.WithCustomAPIURL(srv.URL), hop.WithProjectID("project_test"))
+	require.NoError(t, err)
+	return c.Ignite.Deployments.WritableVolumeFS(context.Background(), "test", "vol"), &streams
+}
+
+// Mounts the fake volume in a temporary directory. The test is skipped if FUSE is unavailable.
+func mountTestVolume(t *testing.T, s *volumetest.Server, cfg mountConfig) string {
+	t.Helper()
+	if _, err := os.Stat("/dev/fuse"); err != nil {
+		t.Skip("FUSE is not available:", err)
+	}
+	vol, _ := newTestVolume(t, s)
+	dir := t.TempDir()
+	cfg.AttrTTL = time.Second
+	cfg.ReadAhead = 1 << 20
+	server, err := mount(dir, vol, cfg)
+	if err != nil {
+		t.Skip("unable to mount FUSE filesystem:", err)
+	}
+	t.Cleanup(func() { _ = server.Unmount() })
+	return dir
+}
+
+func TestToErrno(t *testing.T) {
+	tests := []struct {
+		name string
+
+		err  error
+		want syscall.Errno
+	}{
+		{name: "nil", want: 0},
+		{name: "not exist", err: &iofs.PathError{Err: iofs.ErrNotExist}, want: syscall.ENOENT},
+		{name: "exist", err: &iofs.PathError{Err: iofs.ErrExist}, want: syscall.EEXIST},
+		{name: "invalid", err: &iofs.PathError{Err: iofs.ErrInvalid}, want: syscall.EINVAL},
+		{name: "not empty", err: &iofs.PathError{Err: hop.ErrDirectoryNotEmpty}, want: syscall.ENOTEMPTY},
+		{name: "not a directory", err: &iofs.PathError{Err: hop.ErrNotADirectory}, want: syscall.ENOTDIR},
+		{name: "other", err: errors.New("boom"), want: syscall.EIO},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, toErrno(tt.err))
+		})
+	}
+}
+
+func TestReadHandle_ReadAhead(t *testing.T) {
+	s := volumetest.NewServer()
+	s.AddFile("a.txt", "hello world", 0o644)
+	vol, streams := newTestVolume(t, s)
+	f, err := vol.Open("a.txt")
+	require.NoError(t, err)
+	h := newReadHandle(f, 1<<20)
+	defer h.Release(context.Background())
+
+	read := func(size int, off int64) string {
+		res, errno := h.Read(context.Background(), make([]byte, size), off)
+		require.Equal(t, syscall.Errno(0), errno)
+		b, status := res.Bytes(make([]byte, size))
+		require.True(t, status.Ok())
+		return string(b)
+	}
+	assert.Equal(t, " world", read(6, 5))
+	assert.Equal(t, int32(1), atomic.LoadInt32(streams))
+
+	// Reading before the buffer fetches again, after which everything is buffered.
+	assert.Equal(t, "hello", read(5, 0))
+	assert.Equal(t, " world", read(10, 5))
+	assert.Equal(t, "", read(10, 11))
+	assert.Equal(t, int32(2), atomic.LoadInt32(streams))
+}
+
+func TestMount(t *testing.T) {
+	s := volumetest.NewServer()
+	s.AddFile("a.txt", "hello world", 0o640)
+	s.AddFile("dir/b.txt", "bbbb", 0o644)
+	dir := mountTestVolume(t, s, mountConfig{})
+
+	b, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+
+	info, err := os.Stat(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode())
+	assert.Equal(t, s.Now, info.ModTime().UTC())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a.txt", entries[0].Name())
+	assert.True(t, entries[1].IsDir())
+
+	// Writes are uploaded when the file is closed.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dir", "new.txt"), []byte("new"), 0o600))
+	content, _ := s.ReadFile("dir/new.txt")
+	assert.Equal(t, "new", content)
+
+	f, err := os.OpenFile(filepath.Join(dir, "a.txt"), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString("!")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	content, _ = s.ReadFile("a.txt")
+	assert.Equal(t, "hello world!", content)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "other"), 0o700))
+	require.NoError(t, os.Rename(filepath.Join(dir, "dir", "b.txt"), filepath.Join(dir, "other", "b.txt")))
+	require.NoError(t, os.Chmod(filepath.Join(dir, "other", "b.txt"), 0o600))
+	require.NoError(t, os.Remove(filepath.Join(dir, "dir", "new.txt")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "dir")))
+	err = os.Remove(filepath.Join(dir, "other"))
+	assert.True(t, errors.Is(err, syscall.ENOTEMPTY))
+
+	assert.Equal(t, []string{".", "a.txt", "other", "other/b.txt"}, s.Paths())
+	f2, _ := s.Stat("other/b.txt")
+	assert.Equal(t, 0o600, f2.Perm)
+}
+
+func TestMount_ReadOnly(t *testing.T) {
+	s := volumetest.NewServer()
+	s.NoWrites = true
+	s.AddFile("a.txt", "hello", 0o644)
+	var readOnlyCalls int32
+	dir := mountTestVolume(t, s, mountConfig{OnReadOnly: func() { atomic.AddInt32(&readOnlyCalls, 1) }})
+
+	// Mounting should not write anything to find out that writes are unavailable.
+	assert.Equal(t, int32(0), atomic.LoadInt32(&readOnlyCalls))
+	b, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+
+	err = os.WriteFile(filepath.Join(dir, "b.txt"), []byte("x"), 0o644)
+	assert.True(t, errors.Is(err, syscall.EROFS))
+	err = os.Mkdir(filepath.Join(dir, "dir"), 0o755)
+	assert.True(t, errors.Is(err, syscall.EROFS))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&readOnlyCalls))
+}
+
+func TestMountState_writeErrno(t *testing.T) {
+	s := volumetest.NewServer()
+	s.AddFile("a.txt", "hello", 0o644)
+	vol, _ := newTestVolume(t, s)
+	var readOnlyCalls int
+	state := &mountState{vol: vol, cfg: mountConfig{OnReadOnly: func() { readOnlyCalls++ }}}
+
+	// A missing file is not a sign that writes are unavailable.
+	assert.Equal(t, syscall.ENOENT, state.writeErrno(vol.Remove("missing.txt")))
+	assert.False(t, state.readOnly())
+
+	s.NoWrites = true
+	assert.Equal(t, syscall.EROFS, state.writeErrno(vol.WriteFile("b.txt", []byte("x"), 0o644)))
+	assert.True(t, state.readOnly())
+	assert.Equal(t, syscall.EROFS, state.writeErrno(vol.Mkdir("dir", 0o755)))
+	assert.Equal(t, 1, readOnlyCalls)
+	assert.Equal(t, []string{".", "a.txt"}, s.Paths())
+}
+
+func Test_writesUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+
+		err  error
+		want bool
+	}{
+		{name: "file not found", err: &iofs.PathError{Err: types.NotFound{Code: "file_not_found"}}, want: false},
+		{name: "volume not found", err: &iofs.PathError{Err: types.NotFound{Code: "not_found"}}, want: false},
+		{name: "endpoint not found", err: &iofs.PathError{Err: types.NotFound{Code: "endpoint_not_found"}}, want: true},
+		{name: "forbidden", err: &iofs.PathError{Err: types.UnknownServerError{StatusCode: 403}}, want: false},
+		{name: "method not allowed", err: &iofs.PathError{Err: types.UnknownServerError{StatusCode: 405}}, want: true},
+		{name: "bad request", err: &iofs.PathError{Err: types.UnknownServerError{StatusCode: 409}}, want: false},
+		{name: "other", err: errors.New("boom"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, writesUnsupported(tt.err))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package hop
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"go.hop.io/sdk/types"
+	"golang.org/x/net/publicsuffix"
+)
+
+// DomainCNAMETarget is the hostname which custom domains should point to with a CNAME record. Apex domains cannot have
+// CNAME records, so A and AAAA records for the addresses of this hostname are expected instead. This can be changed
+// if you are using a different Hop environment.
+var DomainCNAMETarget = "proxy.hop.io"
+
+// Used to look up the addresses of DomainCNAMETarget. This is replaced in tests.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// Returns true if the domain has no subdomain. The public suffix list is used, so domains such as example.co.uk are
+// apex domains.
+func isApexDomain(domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	apex, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		// The domain is a public suffix itself, so it cannot have a CNAME record either.
+		return true
+	}
+	return apex == domain
+}
+
+// ExpectedDNSRecords is used to get the DNS records that need to be set for a pending domain to be verified. Returns
+// nil if the domain is not pending. Subdomains need a CNAME record pointing to DomainCNAMETarget, and apex domains
+// need A and AAAA records for its addresses, which are looked up using DNS.
+func ExpectedDNSRecords(ctx context.Context, domain *types.Domain) ([]types.DNSRecord, error) {
+	if domain == nil {
+		return nil, errors.New("domain must not be nil")
+	}
+	if domain.State != types.DomainStatePending {
+		return nil, nil
+	}
+
+	if !isApexDomain(domain.Domain) {
+		return []types.DNSRecord{{Type: types.DNSRecordTypeCNAME, Name: domain.Domain, Value: DomainCNAMETarget}}, nil
+	}
+
+	addrs, err := lookupIPAddr(ctx, DomainCNAMETarget)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]types.DNSRecord, len(addrs))
+	for i, addr := range addrs {
+		recordType := types.DNSRecordTypeA
+		if addr.IP.To4() == nil {
+			recordType = types.DNSRecordTypeAAAA
+		}
+		records[i] = types.DNSRecord{Type: recordType, Name: domain.Domain, Value: addr.IP.String()}
+	}
+	return records, nil
+}
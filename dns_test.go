@@ -0,0 +1,82 @@
+package hop
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.hop.io/sdk/types"
+)
+
+func TestExpectedDNSRecords(t *testing.T) {
+	lookup := lookupIPAddr
+	t.Cleanup(func() { lookupIPAddr = lookup })
+
+	tests := []struct {
+		name string
+
+		domain    types.Domain
+		lookupErr error
+		want      []types.DNSRecord
+		wantErr   error
+	}{
+		{
+			name:   "not pending",
+			domain: types.Domain{Domain: "example.com", State: types.DomainNameSSLActive},
+		},
+		{
+			name:   "subdomain",
+			domain: types.Domain{Domain: "www.example.com", State: types.DomainStatePending},
+			want:   []types.DNSRecord{{Type: types.DNSRecordTypeCNAME, Name: "www.example.com", Value: "proxy.hop.io"}},
+		},
+		{
+			name:   "apex domain",
+			domain: types.Domain{Domain: "example.com", State: types.DomainStatePending},
+			want: []types.DNSRecord{
+				{Type: types.DNSRecordTypeA, Name: "example.com", Value: "192.0.2.1"},
+				{Type: types.DNSRecordTypeAAAA, Name: "example.com", Value: "2001:db8::1"},
+			},
+		},
+		{
+			name:   "subdomain under a multi-label suffix",
+			domain: types.Domain{Domain: "www.example.co.uk", State: types.DomainStatePending},
+			want:   []types.DNSRecord{{Type: types.DNSRecordTypeCNAME, Name: "www.example.co.uk", Value: "proxy.hop.io"}},
+		},
+		{
+			name:   "apex domain under a multi-label suffix",
+			domain: types.Domain{Domain: "example.co.uk.", State: types.DomainStatePending},
+			want: []types.DNSRecord{
+				{Type: types.DNSRecordTypeA, Name: "example.co.uk.", Value: "192.0.2.1"},
+				{Type: types.DNSRecordTypeAAAA, Name: "example.co.uk.", Value: "2001:db8::1"},
+			},
+		},
+		{
+			name:      "lookup error",
+			domain:    types.Domain{Domain: "example.com", State: types.DomainStatePending},
+			lookupErr: errors.New("no such host"),
+			wantErr:   errors.New("no such host"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+				assert.Equal(t, DomainCNAMETarget, host)
+				if tt.lookupErr != nil {
+					return nil, tt.lookupErr
+				}
+				return []net.IPAddr{{IP: net.ParseIP("192.0.2.1")}, {IP: net.ParseIP("2001:db8::1")}}, nil
+			}
+			records, err := ExpectedDNSRecords(context.Background(), &tt.domain)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, records)
+		})
+	}
+}
+
+func TestExpectedDNSRecords_Nil(t *testing.T) {
+	records, err := ExpectedDNSRecords(context.Background(), nil)
+	assert.EqualError(t, err, "domain must not be nil")
+	assert.Nil(t, records)
+}
@@ -0,0 +1,236 @@
+package hop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// Defines the stream bytes which prefix each binary exec message.
+const (
+	execStreamStdin byte = iota
+	execStreamStdout
+	execStreamStderr
+	execStreamStatus
+	execStreamResize
+	execStreamStdinClosed
+)
+
+// TerminalSize is used to define the size of a terminal in characters.
+type TerminalSize struct {
+	// Width is the number of columns.
+	Width uint16 `json:"width"`
+
+	// Height is the number of rows.
+	Height uint16 `json:"height"`
+}
+
+type execStart struct {
+	Cmd  []string      `json:"cmd"`
+	TTY  bool          `json:"tty"`
+	Size *TerminalSize `json:"size"`
+}
+
+type execStatus struct {
+	ExitCode int `json:"exit_code"`
+}
+
+// ErrExecSessionClosed is returned when the exec session was closed before the process exited.
+var ErrExecSessionClosed = errors.New("exec session closed before the process exited")
+
+// ExecSession is used to define a command running inside a container. Writing to the session writes to the stdin of
+// the process. Please use Exec or ExecInteractive to create this.
+type ExecSession struct {
+	conn           *websocket.Conn
+	stdout, stderr io.Writer
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	done      chan struct{}
+	exitCode  int
+	err       error
+}
+
+// Starts the exec session and the read loop.
+func startExecSession(
+	ctx context.Context, c clientDoer, containerId string, start execStart, stdout, stderr io.Writer,
+	opts []ClientOption,
+) (*ExecSession, error) {
+	conn, err := c.dialWebsocket(ctx, "/ignite/containers/"+url.PathEscape(containerId)+"/exec", nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = conn.WriteJSON(start); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	s := &ExecSession{conn: conn, stdout: stdout, stderr: stderr, done: make(chan struct{})}
+	go s.readLoop()
+	return s, nil
+}
+
+// Reads messages from the websocket until the exit status is received or the connection is closed.
+func (s *ExecSession) readLoop() {
+	defer close(s.done)
+	defer s.conn.Close()
+	for {
+		msgType, b, err := s.conn.ReadMessage()
+		if err != nil {
+			s.err = ErrExecSessionClosed
+			return
+		}
+		if msgType != websocket.BinaryMessage || len(b) == 0 {
+			// Ignore any messages we do not understand.
+			continue
+		}
+
+		var w io.Writer
+		switch b[0] {
+		case execStreamStdout:
+			w = s.stdout
+		case execStreamStderr:
+			w = s.stderr
+		case execStreamStatus:
+			var status execStatus
+			if err = json.Unmarshal(b[1:], &status); err != nil {
+				s.err = err
+			} else {
+				s.exitCode = status.ExitCode
+			}
+			return
+		}
+		if w != nil {
+			if _, err = w.Write(b[1:]); err != nil {
+				s.err = err
+				return
+			}
+		}
+	}
+}
+
+// Writes a binary message with the stream byte specified.
+func (s *ExecSession) writeStream(stream byte, b []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteMessage(websocket.BinaryMessage, append([]byte{stream}, b...))
+}
+
+// Write is used to write to the stdin of the process.
+func (s *ExecSession) Write(b []byte) (int, error) {
+	if err := s.writeStream(execStreamStdin, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Used to copy stdin to a session. Writes fail once the session is done, so that the copy stops even if the websocket
+// has not returned an error yet.
+type execStdinWriter struct {
+	s *ExecSession
+}
+
+func (w execStdinWriter) Write(b []byte) (int, error) {
+	select {
+	case <-w.s.done:
+		return 0, io.ErrClosedPipe
+	default:
+		return w.s.Write(b)
+	}
+}
+
+// CloseStdin is used to close the stdin of the process. Any further writes will be ignored by the process.
+func (s *ExecSession) CloseStdin() error {
+	return s.writeStream(execStreamStdinClosed, nil)
+}
+
+// Resize is used to resize the terminal of an interactive session.
+func (s *ExecSession) Resize(size TerminalSize) error {
+	b, err := json.Marshal(size)
+	if err != nil {
+		return err
+	}
+	return s.writeStream(execStreamResize, b)
+}
+
+// Wait is used to wait for the process to exit. Returns the exit code of the process.
+func (s *ExecSession) Wait() (int, error) {
+	<-s.done
+	return s.exitCode, s.err
+}
+
+// Close is used to close the session. This does not wait for the process to exit.
+func (s *ExecSession) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		select {
+		case <-s.done:
+			// The connection was already closed by the read loop.
+			return
+		default:
+		}
+		s.writeMu.Lock()
+		_ = s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		s.writeMu.Unlock()
+		err = s.conn.Close()
+	})
+	<-s.done
+	return err
+}
+
+// Exec is used to run a command inside a container and wait for it to exit. Stdin is copied to the process until it
+// returns EOF, and the output of the process is written to stdout and stderr. Any of the streams can be nil. Returns
+// the exit code of the process.
+//
+// The caller owns stdin and Exec never closes it. Stdin is read in the background, and a read which is blocked when
+// the process exits (for example on os.Stdin) keeps running until it returns. If stdin can block, the caller should
+// close it or make it return EOF once Exec returns so that the copy stops. Any data read after the process exits is
+// discarded.
+func (c ClientCategoryIgniteContainers) Exec(
+	ctx context.Context, containerId string, cmd []string, stdin io.Reader, stdout, stderr io.Writer,
+	opts ...ClientOption,
+) (int, error) {
+	s, err := startExecSession(ctx, c.c, containerId, execStart{Cmd: cmd}, stdout, stderr, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer s.Close()
+
+	// Close the session if the context is cancelled.
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = s.Close()
+		case <-s.done:
+		}
+	}()
+
+	// Copy stdin to the process. This stops at the first read which returns after the session is done.
+	go func() {
+		if stdin != nil {
+			if _, copyErr := io.Copy(execStdinWriter{s: s}, stdin); copyErr != nil {
+				return
+			}
+		}
+		_ = s.CloseStdin()
+	}()
+
+	exitCode, err := s.Wait()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return 0, ctxErr
+	}
+	return exitCode, err
+}
+
+// ExecInteractive is used to start an interactive TTY session running the command specified inside a container. The
+// context is only used when connecting. The terminal output is written to stdout since a TTY does not separate stdout
+// and stderr. The session MUST be closed by the caller.
+func (c ClientCategoryIgniteContainers) ExecInteractive(
+	ctx context.Context, containerId string, cmd []string, stdout io.Writer, size TerminalSize, opts ...ClientOption,
+) (*ExecSession, error) {
+	return startExecSession(ctx, c.c, containerId, execStart{Cmd: cmd, TTY: true, Size: &size}, stdout, nil, opts)
+}
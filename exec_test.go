@@ -0,0 +1,187 @@
+package hop
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+// Defines a stand-in for the exec websocket. The process echoes stdin back on stdout in upper case, writes a line to
+// stderr, and exits with the number of resizes when stdin is closed.
+type execTestServer struct {
+	t *testing.T
+
+	mu      sync.Mutex
+	start   execStart
+	resizes []TerminalSize
+}
+
+func (s *execTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/ignite/containers/container_1/exec" {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"success":false,"error":{"code":"not_found","message":"Container not found"}}`))
+		return
+	}
+	assert.Equal(s.t, "pat_test", r.Header.Get("Authorization"))
+	assert.Equal(s.t, userAgent, r.Header.Get("User-Agent"))
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if !assert.NoError(s.t, err) {
+		return
+	}
+	defer conn.Close()
+
+	s.mu.Lock()
+	err = conn.ReadJSON(&s.start)
+	drop := len(s.start.Cmd) != 0 && s.start.Cmd[0] == "drop"
+	s.mu.Unlock()
+	if !assert.NoError(s.t, err) || drop {
+		// Drop the connection without sending a status.
+		return
+	}
+
+	send := func(stream byte, b []byte) {
+		assert.NoError(s.t, conn.WriteMessage(websocket.BinaryMessage, append([]byte{stream}, b...)))
+	}
+	send(execStreamStderr, []byte("starting\n"))
+	for {
+		_, b, readErr := conn.ReadMessage()
+		if readErr != nil {
+			return
+		}
+		switch b[0] {
+		case execStreamStdin:
+			send(execStreamStdout, bytes.ToUpper(b[1:]))
+		case execStreamResize:
+			var size TerminalSize
+			assert.NoError(s.t, json.Unmarshal(b[1:], &size))
+			s.mu.Lock()
+			s.resizes = append(s.resizes, size)
+			s.mu.Unlock()
+		case execStreamStdinClosed:
+			s.mu.Lock()
+			status, _ := json.Marshal(execStatus{ExitCode: len(s.resizes)})
+			s.mu.Unlock()
+			send(execStreamStatus, status)
+			return
+		}
+	}
+}
+
+func newExecTestClient(t *testing.T) (*execTestServer, ClientCategoryIgniteContainers) {
+	t.Helper()
+	s := &execTestServer{t: t}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	c, err := NewClient("pat_test", WithCustomAPIURL(srv.URL))
+	require.NoError(t, err)
+	return s, ClientCategoryIgniteContainers{c: c}
+}
+
+func TestClient_Ignite_Containers_Exec(t *testing.T) {
+	s, c := newExecTestClient(t)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	exitCode, err := c.Exec(context.Background(), "container_1", []string{"cat"},
+		strings.NewReader("hello world"), stdout, stderr)
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, "HELLO WORLD", stdout.String())
+	assert.Equal(t, "starting\n", stderr.String())
+	assert.Equal(t, execStart{Cmd: []string{"cat"}}, s.start)
+}
+
+func TestClient_Ignite_Containers_Exec_NotFound(t *testing.T) {
+	_, c := newExecTestClient(t)
+	_, err := c.Exec(context.Background(), "container_2", []string{"cat"}, nil, nil, nil)
+	assert.Equal(t, types.NotFound{Code: "not_found", Message: "Container not found"}, err)
+}
+
+func TestClient_Ignite_Containers_Exec_Dropped(t *testing.T) {
+	_, c := newExecTestClient(t)
+	sess, err := c.ExecInteractive(context.Background(), "container_1", []string{"drop"}, nil, TerminalSize{})
+	require.NoError(t, err)
+	_, err = sess.Wait()
+	assert.Equal(t, ErrExecSessionClosed, err)
+
+	// The read loop should have closed the connection, since Close does nothing once the session is done.
+	assert.ErrorIs(t, sess.conn.WriteMessage(websocket.BinaryMessage, []byte{execStreamStdin}), net.ErrClosed)
+	assert.NoError(t, sess.Close())
+}
+
+// Defines a writer which signals each write on a channel.
+type chanWriter chan string
+
+func (c chanWriter) Write(b []byte) (int, error) {
+	c <- string(b)
+	return len(b), nil
+}
+
+func TestClient_Ignite_Containers_ExecInteractive(t *testing.T) {
+	s, c := newExecTestClient(t)
+	stdout := make(chanWriter, 10)
+	sess, err := c.ExecInteractive(context.Background(), "container_1", []string{"sh"}, stdout,
+		TerminalSize{Width: 80, Height: 24})
+	require.NoError(t, err)
+	defer sess.Close()
+
+	// TTY sessions do not have a separate stderr, so the first output is the echo.
+	_, err = sess.Write([]byte("ls\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "LS\n", <-stdout)
+
+	require.NoError(t, sess.Resize(TerminalSize{Width: 120, Height: 40}))
+	require.NoError(t, sess.Resize(TerminalSize{Width: 100, Height: 30}))
+	require.NoError(t, sess.CloseStdin())
+	exitCode, err := sess.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, 2, exitCode)
+	assert.NoError(t, sess.Close())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Equal(t, execStart{Cmd: []string{"sh"}, TTY: true, Size: &TerminalSize{Width: 80, Height: 24}}, s.start)
+	assert.Equal(t, []TerminalSize{{Width: 120, Height: 40}, {Width: 100, Height: 30}}, s.resizes)
+}
+
+func TestClient_Ignite_Containers_Exec_ContextCancelled(t *testing.T) {
+	_, c := newExecTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stdin, stdinW := io.Pipe()
+	defer stdinW.Close()
+	stderr := make(chanWriter, 10)
+	done := make(chan error)
+	go func() {
+		_, err := c.Exec(ctx, "container_1", []string{"cat"}, stdin, nil, stderr)
+		done <- err
+	}()
+	<-stderr
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	// The blocked read of stdin returns after Exec has returned, and the copy should then stop reading.
+	_, err := stdinW.Write([]byte("late"))
+	require.NoError(t, err)
+	written := make(chan struct{})
+	go func() {
+		_, _ = stdinW.Write([]byte("later"))
+		close(written)
+	}()
+	select {
+	case <-written:
+		t.Fatal("stdin was read after the session was done")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -1,6 +1,7 @@
 package hop
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,6 +10,9 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,9 +28,10 @@ type dirItem struct {
 }
 
 type volumeFile struct {
-	// Defines the read closer.
+	// Defines the read closer and the offset it is at.
 	rcLock sync.Mutex
 	rc     io.ReadCloser
+	offset int64
 
 	// Defines if this is a file.
 	isFile bool
@@ -37,24 +42,72 @@ type volumeFile struct {
 	ctx  context.Context
 	opts []ClientOption
 
-	// Defines the file information.
+	// Defines the file information. The directory listing is sorted by name.
+	fsys       volumeFs
 	filename   string
 	dirListing []*dirItem
+
+	// Defines the lazily loaded file information and the directory read offset.
+	infoLock  sync.Mutex
+	info      fs.FileInfo
+	dirOffset int
 }
 
-func (f *volumeFile) getReadStream() error {
+// ErrRangeNotSupported is returned when a volume file is read from an offset and the server does not support range
+// requests.
+var ErrRangeNotSupported = errors.New("server does not support range requests for volume files")
+
+// Returns the error used when the file is a directory.
+func (f *volumeFile) isDirError(op string) error {
+	// This is unintuitive, but this is the right error to emulate a bad path.
+	return &fs.PathError{
+		Op:   op,
+		Path: f.filename,
+
+		// this feels *close enough* since libraries probably should not be relying
+		// on OS specific error codes and this is the error content you'd expect on
+		// anything POSIX based.
+		Err: errors.New("is a directory"),
+	}
+}
+
+// Gets a stream of the file starting at the offset specified. If end is not -1, the stream stops after the byte at
+// that offset.
+func (f *volumeFile) getRangeStream(offset, end int64) (io.ReadCloser, error) {
+	var headers map[string]string
+	if offset != 0 || end != -1 {
+		rangeHeader := "bytes=" + strconv.FormatInt(offset, 10) + "-"
+		if end != -1 {
+			rangeHeader += strconv.FormatInt(end, 10)
+		}
+		headers = map[string]string{"Range": rangeHeader}
+	}
+
 	var resp *http.Response
 	err := f.c.do(f.ctx, ClientArgs{
 		Method:      "GET",
 		Path:        f.path,
 		Query:       map[string]string{"stream": "true"},
+		Headers:     headers,
 		Ignore404:   false,
 		PassRequest: func(r *http.Response) { resp = r },
 	}, f.opts)
+	if err != nil {
+		return nil, err
+	}
+	if headers != nil && resp.StatusCode != http.StatusPartialContent {
+		_ = resp.Body.Close()
+		return nil, &fs.PathError{Op: "read", Path: f.filename, Err: ErrRangeNotSupported}
+	}
+	return resp.Body, nil
+}
+
+func (f *volumeFile) getReadStream() error {
+	rc, err := f.getRangeStream(f.offset, -1)
 	if err != nil {
 		return err
 	}
-	f.rc = resp.Body
+	f.rc = rc
 	return nil
 }
 
@@ -62,32 +115,97 @@ func (f *volumeFile) Read(b []byte) (int, error) {
 	f.rcLock.Lock()
 	defer f.rcLock.Unlock()
 
-	if f.rc != nil {
-		// Reader already here meaning checks have been passed.
-		return f.rc.Read(b)
+	if f.rc == nil {
+		if !f.isFile {
+			return 0, f.isDirError("read")
+		}
+		if f.offset != 0 && f.offset >= f.dirListing[0].Size {
+			// Nothing is left to read, and the server would reject the range.
+			return 0, io.EOF
+		}
+		if err := f.getReadStream(); err != nil {
+			return 0, err
+		}
 	}
 
-	if !f.isFile {
-		// This is unintuitive, but this is the right error to emulate a bad path.
-		return 0, &fs.PathError{
-			Op:   "read",
-			Path: f.filename,
+	n, err := f.rc.Read(b)
+	f.offset += int64(n)
+	return n, err
+}
 
-			// this feels *close enough* since libraries probably should not be relying
-			// on OS specific error codes and this is the error content you'd expect on
-			// anything POSIX based.
-			Err: errors.New("is a directory"),
-		}
+// ReadAt is used to read the bytes at the offset specified using a range request. This does not change the offset
+// used by Read.
+func (f *volumeFile) ReadAt(b []byte, off int64) (int, error) {
+	if !f.isFile {
+		return 0, f.isDirError("read")
+	}
+	if off < 0 {
+		return 0, &fs.PathError{Op: "read", Path: f.filename, Err: errors.New("negative offset")}
+	}
+	size := f.dirListing[0].Size
+	if off >= size {
+		return 0, io.EOF
+	}
+	want := b
+	if int64(len(want)) > size-off {
+		want = want[:size-off]
+	}
+	if len(want) == 0 {
+		return 0, nil
 	}
 
-	err := f.getReadStream()
-	var n int
-	if err == nil {
-		n, err = f.rc.Read(b)
+	rc, err := f.getRangeStream(off, off+int64(len(want))-1)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	n, err := io.ReadFull(rc, want)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	if err == nil && len(want) < len(b) {
+		// We hit the end of the file.
+		err = io.EOF
 	}
 	return n, err
 }
 
+// Seek is used to set the offset for the next Read. Reading from anywhere but the start of the file uses a range
+// request.
+func (f *volumeFile) Seek(offset int64, whence int) (int64, error) {
+	if !f.isFile {
+		return 0, f.isDirError("seek")
+	}
+
+	f.rcLock.Lock()
+	defer f.rcLock.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.dirListing[0].Size
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.filename, Err: fs.ErrInvalid}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.filename, Err: fs.ErrInvalid}
+	}
+	if offset != f.offset && f.rc != nil {
+		// The stream is at the wrong place, so a new one is made on the next read.
+		_ = f.rc.Close()
+		f.rc = nil
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+var (
+	_ io.ReaderAt = (*volumeFile)(nil)
+	_ io.Seeker   = (*volumeFile)(nil)
+)
+
 func (f *volumeFile) Close() error {
 	f.rcLock.Lock()
 	defer f.rcLock.Unlock()
@@ -98,65 +216,124 @@ func (f *volumeFile) Close() error {
 	return nil
 }
 
-func (f *volumeFile) Mode() fs.FileMode {
-	if f.isFile {
-		return fs.FileMode(f.dirListing[0].Permissions)
+// Returns the file information for the directory by finding it in the listing of its parent. The root directory
+// has no parent, so the information is aggregated from its contents instead.
+func (f *volumeFile) dirInfo() (fs.FileInfo, error) {
+	if f.filename == "." {
+		root := &dirItem{Name: ".", Directory: true, Permissions: 0o777}
+		var latestTime time.Time
+		for _, v := range f.dirListing {
+			root.Size += v.Size
+			if t, _ := v.UpdatedAt.Time(); t.After(latestTime) {
+				latestTime = t
+				root.UpdatedAt = v.UpdatedAt
+			}
+		}
+		return volumeFileInfo{root}, nil
 	}
-	return 0o777
-}
 
-func (f *volumeFile) Stat() (fs.FileInfo, error) {
-	// Return ourselves since we implement both.
-	return f, nil
-}
+	dir, name := path.Split(f.filename)
+	entries, err := f.fsys.ReadDir(path.Clean(dir))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range entries {
+		if v.Name() == name {
+			return v.Info()
+		}
+	}
 
-func (f *volumeFile) Name() string {
-	_, file := path.Split(f.filename)
-	return file
+	// The directory was removed between the requests.
+	return nil, &fs.PathError{Op: "stat", Path: f.filename, Err: fs.ErrNotExist}
 }
 
-func (f *volumeFile) Size() int64 {
+func (f *volumeFile) Stat() (fs.FileInfo, error) {
+	f.infoLock.Lock()
+	defer f.infoLock.Unlock()
+
+	if f.info != nil {
+		return f.info, nil
+	}
 	if f.isFile {
-		return f.dirListing[0].Size
+		item := *f.dirListing[0]
+		item.Name = path.Base(f.filename)
+		f.info = volumeFileInfo{&item}
+		return f.info, nil
 	}
-
-	var size int64
-	for _, v := range f.dirListing {
-		size += v.Size
+	info, err := f.dirInfo()
+	if err != nil {
+		return nil, err
 	}
-	return size
+	f.info = info
+	return info, nil
 }
 
-func (f *volumeFile) ModTime() time.Time {
+// ReadDir is used to read the contents of the directory in order. If n is above 0, at most n entries are returned
+// and io.EOF is returned at the end of the directory. Otherwise, all the remaining entries are returned.
+func (f *volumeFile) ReadDir(n int) ([]fs.DirEntry, error) {
 	if f.isFile {
-		t, _ := f.dirListing[0].UpdatedAt.Time()
-		return t
+		return nil, &fs.PathError{Op: "readdir", Path: f.filename, Err: ErrNotADirectory}
 	}
 
-	// Find the latest time.
-	var latestTime time.Time
-	for _, v := range f.dirListing {
-		t, _ := v.UpdatedAt.Time()
-		if t.After(latestTime) {
-			latestTime = t
+	f.infoLock.Lock()
+	defer f.infoLock.Unlock()
+
+	remaining := f.dirListing[f.dirOffset:]
+	if n > 0 {
+		if len(remaining) == 0 {
+			return nil, io.EOF
+		}
+		if len(remaining) > n {
+			remaining = remaining[:n]
 		}
 	}
-	return latestTime
+	f.dirOffset += len(remaining)
+	entries := make([]fs.DirEntry, len(remaining))
+	for i, v := range remaining {
+		entries[i] = volumeFileInfo{v}
+	}
+	return entries, nil
+}
+
+var _ fs.ReadDirFile = (*volumeFile)(nil)
+
+// Defines the file information for an item in a volume. This is both a fs.FileInfo and fs.DirEntry.
+type volumeFileInfo struct {
+	item *dirItem
 }
 
-func (f *volumeFile) IsDir() bool {
-	return !f.isFile
+func (i volumeFileInfo) Name() string { return i.item.Name }
+
+func (i volumeFileInfo) Size() int64 { return i.item.Size }
+
+func (i volumeFileInfo) Mode() fs.FileMode {
+	mode := fs.FileMode(i.item.Permissions).Perm()
+	if i.item.Directory {
+		mode |= fs.ModeDir
+	}
+	return mode
 }
 
-func (f *volumeFile) Sys() any {
+func (i volumeFileInfo) ModTime() time.Time {
+	t, _ := i.item.UpdatedAt.Time()
+	return t
+}
+
+func (i volumeFileInfo) IsDir() bool { return i.item.Directory }
+
+func (i volumeFileInfo) Sys() any {
 	// It is within spec to not implement this, and due to the emulation
 	// nature of this, we do not.
 	return nil
 }
 
+func (i volumeFileInfo) Type() fs.FileMode { return i.Mode().Type() }
+
+func (i volumeFileInfo) Info() (fs.FileInfo, error) { return i, nil }
+
 var (
-	_ fs.File     = (*volumeFile)(nil)
-	_ fs.FileInfo = (*volumeFile)(nil)
+	_ fs.FileInfo = volumeFileInfo{}
+	_ fs.DirEntry = volumeFileInfo{}
 )
 
 type volumeFs struct {
@@ -165,25 +342,41 @@ type volumeFs struct {
 	deploymentId string
 	volumeId     string
 	opts         []ClientOption
+
+	// Defines the directory inside the volume this filesystem is rooted at. Blank for the root of the volume.
+	root string
 }
 
 const fileNotFoundCode = "file_not_found"
 
+// Returns the API path for the file specified.
+func (f volumeFs) filesPath(name string) string {
+	name = path.Join(f.root, name)
+	urlChunk := ""
+	if name != "" && name != "." {
+		urlChunk = "/" + url.PathEscape(name)
+	}
+	return volumePath(f.deploymentId, f.volumeId) + "/files" + urlChunk
+}
+
 func (f volumeFs) Open(name string) (fs.File, error) {
+	file, err := f.open(name)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Opens the file specified.
+func (f volumeFs) open(name string) (*volumeFile, error) {
 	// Run fs package validations.
 	v := fs.ValidPath(name)
 	if !v {
-		return nil, fs.ErrInvalid
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
 	}
 
 	// Get the path.
-	urlChunk := ""
-	if name != "" && name != "." {
-		urlChunk = "/" + url.PathEscape(name)
-	}
-	urlChunk = "/ignite/deployments/" +
-		url.PathEscape(f.deploymentId) + "/volumes/" +
-		url.PathEscape(f.volumeId) + "/files" + urlChunk
+	urlChunk := f.filesPath(name)
 
 	// Make the network request.
 	var resp struct {
@@ -206,7 +399,7 @@ func (f volumeFs) Open(name string) (fs.File, error) {
 		}
 
 		// Throw a not found error.
-		return nil, fs.ErrNotExist
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
 	}
 
 	// Get the files.
@@ -215,6 +408,7 @@ func (f volumeFs) Open(name string) (fs.File, error) {
 		if err = json.Unmarshal(resp.File, &files); err != nil {
 			return nil, err
 		}
+		sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
 	} else {
 		var file dirItem
 		if err = json.Unmarshal(resp.File, &file); err != nil {
@@ -230,12 +424,87 @@ func (f volumeFs) Open(name string) (fs.File, error) {
 		c:          f.c,
 		ctx:        f.ctx,
 		opts:       f.opts,
+		fsys:       f,
 		filename:   name,
 		dirListing: files,
 	}, nil
 }
 
-var _ fs.FS = volumeFs{}
+// Opens the file specified and runs the function with it.
+func (f volumeFs) withFile(op, name string, fn func(file *volumeFile) error) error {
+	file, err := f.open(name)
+	if err != nil {
+		if pathErr, ok := err.(*fs.PathError); ok {
+			pathErr.Op = op
+		}
+		return err
+	}
+	defer file.Close()
+	return fn(file)
+}
+
+// Stat is used to get the file information for the path specified.
+func (f volumeFs) Stat(name string) (fs.FileInfo, error) {
+	var info fs.FileInfo
+	err := f.withFile("stat", name, func(file *volumeFile) error {
+		var err error
+		info, err = file.Stat()
+		return err
+	})
+	return info, err
+}
+
+// ReadDir is used to read the directory specified. The entries are sorted by name.
+func (f volumeFs) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	err := f.withFile("readdir", name, func(file *volumeFile) error {
+		var err error
+		entries, err = file.ReadDir(-1)
+		return err
+	})
+	return entries, err
+}
+
+// ReadFile is used to read the whole file specified.
+func (f volumeFs) ReadFile(name string) ([]byte, error) {
+	var b []byte
+	err := f.withFile("readfile", name, func(file *volumeFile) error {
+		if !file.isFile {
+			return &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+		}
+		var err error
+		b, err = io.ReadAll(file)
+		return err
+	})
+	return b, err
+}
+
+// Sub is used to get a filesystem rooted at the directory specified.
+func (f volumeFs) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	if dir == "." {
+		return f, nil
+	}
+	info, err := f.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: ErrNotADirectory}
+	}
+	f.root = path.Join(f.root, dir)
+	return f, nil
+}
+
+var (
+	_ fs.FS         = volumeFs{}
+	_ fs.StatFS     = volumeFs{}
+	_ fs.ReadDirFS  = volumeFs{}
+	_ fs.ReadFileFS = volumeFs{}
+	_ fs.SubFS      = volumeFs{}
+)
 
 // VolumeVirtualFS is used to make a fs.FS compatible virtual filesystem.
 // Note that the context should live as long as the filesystem in this instance.
@@ -250,3 +519,255 @@ func (c ClientCategoryIgniteDeployments) VolumeVirtualFS(
 		opts:         opts,
 	}
 }
+
+// Defines the error codes the files API uses for path errors.
+const (
+	fileExistsCode        = "file_exists"
+	directoryNotEmptyCode = "directory_not_empty"
+	notADirectoryCode     = "not_a_directory"
+)
+
+var (
+	// ErrDirectoryNotEmpty is wrapped by the error returned when removing a volume directory which is not empty.
+	ErrDirectoryNotEmpty = errors.New("directory not empty")
+
+	// ErrNotADirectory is wrapped by the error returned when a volume path is used as a directory but is a file.
+	ErrNotADirectory = errors.New("not a directory")
+)
+
+// Turns an error from the files API into a *fs.PathError.
+func volumePathError(op, name string, err error) error {
+	var code string
+	switch x := err.(type) {
+	case types.NotFound:
+		code = x.Code
+	case types.BadRequest:
+		code = x.Code
+	case types.UnknownServerError:
+		code = x.Code
+	}
+	switch code {
+	case fileNotFoundCode:
+		err = fs.ErrNotExist
+	case fileExistsCode:
+		err = fs.ErrExist
+	case directoryNotEmptyCode:
+		err = ErrDirectoryNotEmpty
+	case notADirectoryCode:
+		err = ErrNotADirectory
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// WritableVolumeFS is used to define a virtual filesystem for a volume which can be written to. Reads are done through
+// the fs.FS implementation, so any io/fs helpers work with this. Please use WritableVolumeFS on the deployments
+// category to create this.
+type WritableVolumeFS struct {
+	volumeFs
+}
+
+// WritableVolumeFS is used to make a writable virtual filesystem for a volume. Paths follow the io/fs rules, so they
+// are slash separated and relative to the root of the volume.
+// Note that the context should live as long as the filesystem in this instance.
+func (c ClientCategoryIgniteDeployments) WritableVolumeFS(
+	ctx context.Context, deploymentId, volumeId string, opts ...ClientOption,
+) *WritableVolumeFS {
+	return &WritableVolumeFS{volumeFs{
+		c:            c.c,
+		ctx:          ctx,
+		deploymentId: deploymentId,
+		volumeId:     volumeId,
+		opts:         opts,
+	}}
+}
+
+// Returns a *fs.PathError if the path cannot be changed.
+func validateWritablePath(op, name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return nil
+}
+
+// Upload is used to stream the contents of the reader to the file specified, creating or truncating it. The parent
+// directory must exist.
+func (f *WritableVolumeFS) Upload(name string, r io.Reader, perm fs.FileMode) error {
+	if err := validateWritablePath("open", name); err != nil {
+		return err
+	}
+	err := f.c.do(f.ctx, ClientArgs{
+		Method: "PUT",
+		Path:   f.filesPath(name),
+		Query:  map[string]string{"permissions": strconv.Itoa(int(perm.Perm()))},
+		Body:   ReaderBody{ContentType: "application/octet-stream", Reader: r},
+	}, f.opts)
+	if err != nil {
+		return volumePathError("open", name, err)
+	}
+	return nil
+}
+
+// WriteFile is used to write the data to the file specified, creating or truncating it. The parent directory must
+// exist.
+func (f *WritableVolumeFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return f.Upload(name, bytes.NewReader(data), perm)
+}
+
+// Defines a file which is being streamed to the volume.
+type volumeFileWriter struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (w *volumeFileWriter) Write(b []byte) (int, error) {
+	return w.pw.Write(b)
+}
+
+// Close finishes the upload and returns any error from the API.
+func (w *volumeFileWriter) Close() error {
+	_ = w.pw.Close()
+	return <-w.done
+}
+
+// Create is used to create or truncate the file specified with the permissions 0644. Anything written is streamed to
+// the volume as it is written, and the upload is finished when the writer is closed. The error from the API is
+// returned by Close, so it MUST be checked.
+func (f *WritableVolumeFS) Create(name string) (io.WriteCloser, error) {
+	if err := validateWritablePath("open", name); err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	w := &volumeFileWriter{pw: pw, done: make(chan error, 1)}
+	go func() {
+		err := f.Upload(name, pr, 0o644)
+		// Unblock any writes if the request failed early.
+		_ = pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+// Mkdir is used to create a directory. The parent directory must exist.
+func (f *WritableVolumeFS) Mkdir(name string, perm fs.FileMode) error {
+	if err := validateWritablePath("mkdir", name); err != nil {
+		return err
+	}
+	err := f.c.do(f.ctx, ClientArgs{
+		Method: "POST",
+		Path:   f.filesPath(name),
+		Body: map[string]any{
+			"directory":   true,
+			"permissions": int(perm.Perm()),
+		},
+	}, f.opts)
+	if err != nil {
+		return volumePathError("mkdir", name, err)
+	}
+	return nil
+}
+
+// MkdirAll is used to create a directory along with any parents which do not exist. Returns nil if the directory
+// already exists.
+func (f *WritableVolumeFS) MkdirAll(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return nil
+	}
+	dir := ""
+	for _, chunk := range strings.Split(name, "/") {
+		dir = path.Join(dir, chunk)
+		err := f.Mkdir(dir, perm)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return err
+		}
+
+		// Make sure that what exists is a directory.
+		file, openErr := f.Open(dir)
+		if openErr != nil {
+			return openErr
+		}
+		info, statErr := file.Stat()
+		_ = file.Close()
+		if statErr != nil {
+			return statErr
+		}
+		if !info.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: dir, Err: ErrNotADirectory}
+		}
+	}
+	return nil
+}
+
+// Removes the file or directory specified.
+func (f *WritableVolumeFS) remove(name string, recursive bool) error {
+	if err := validateWritablePath("remove", name); err != nil {
+		return err
+	}
+	var query map[string]string
+	if recursive {
+		query = map[string]string{"recursive": "true"}
+	}
+	err := f.c.do(f.ctx, ClientArgs{
+		Method: "DELETE",
+		Path:   f.filesPath(name),
+		Query:  query,
+	}, f.opts)
+	if err != nil {
+		return volumePathError("remove", name, err)
+	}
+	return nil
+}
+
+// Remove is used to remove a file or empty directory.
+func (f *WritableVolumeFS) Remove(name string) error {
+	return f.remove(name, false)
+}
+
+// RemoveAll is used to remove a file or directory and anything it contains. Returns nil if the path does not exist.
+func (f *WritableVolumeFS) RemoveAll(name string) error {
+	err := f.remove(name, true)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Rename is used to move a file or directory. The parent directory of the new path must exist.
+func (f *WritableVolumeFS) Rename(oldname, newname string) error {
+	if err := validateWritablePath("rename", oldname); err != nil {
+		return err
+	}
+	if err := validateWritablePath("rename", newname); err != nil {
+		return err
+	}
+	err := f.c.do(f.ctx, ClientArgs{
+		Method: "PATCH",
+		Path:   f.filesPath(oldname),
+		Body:   map[string]string{"path": newname},
+	}, f.opts)
+	if err != nil {
+		return volumePathError("rename", oldname, err)
+	}
+	return nil
+}
+
+// Chmod is used to change the permissions of a file or directory. Only the permission bits of the mode are used.
+func (f *WritableVolumeFS) Chmod(name string, mode fs.FileMode) error {
+	if err := validateWritablePath("chmod", name); err != nil {
+		return err
+	}
+	err := f.c.do(f.ctx, ClientArgs{
+		Method: "PATCH",
+		Path:   f.filesPath(name),
+		Body:   map[string]int{"permissions": int(mode.Perm())},
+	}, f.opts)
+	if err != nil {
+		return volumePathError("chmod", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,419 @@
+package hop
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// VolumeSyncAction is used to define an action taken by a volume sync.
+type VolumeSyncAction string
+
+const (
+	// VolumeSyncActionMkdir is used when a directory is created in the destination.
+	VolumeSyncActionMkdir VolumeSyncAction = "mkdir"
+
+	// VolumeSyncActionTransfer is used when a file is copied to the destination.
+	VolumeSyncActionTransfer VolumeSyncAction = "transfer"
+
+	// VolumeSyncActionDelete is used when a file or directory is removed from the destination.
+	VolumeSyncActionDelete VolumeSyncAction = "delete"
+)
+
+// VolumeSyncItem is used to define a change made by a volume sync, or a change that would be made in a dry run.
+type VolumeSyncItem struct {
+	// Path is the slash separated path relative to the root of the sync.
+	Path string `json:"path"`
+
+	// Action is the action taken on the path.
+	Action VolumeSyncAction `json:"action"`
+
+	// Size is the size of the file in bytes. This is 0 for anything but transfers.
+	Size int64 `json:"size"`
+
+	// Err is the error that happened when doing the action. This is nil on success.
+	Err error `json:"-"`
+}
+
+// VolumeSyncOptions is used to define the options for syncing a volume.
+type VolumeSyncOptions struct {
+	// Concurrency is the maximum number of files transferred at once. If this is 0, it will default to 4.
+	Concurrency int
+
+	// Delete is used to delete files and directories in the destination which are not in the source.
+	Delete bool
+
+	// DryRun is used to only report the changes that would be made.
+	DryRun bool
+
+	// Progress is called after each change is made, or when each change is planned in a dry run. Calls are never made
+	// at the same time. Can be nil.
+	Progress func(VolumeSyncItem)
+}
+
+// VolumeSyncReport is used to define the result of a volume sync.
+type VolumeSyncReport struct {
+	// Items are the changes in the order they were made.
+	Items []VolumeSyncItem `json:"items"`
+
+	// Unchanged is the number of files which were already up to date.
+	Unchanged int `json:"unchanged"`
+
+	// BytesTransferred is the total size of the files transferred.
+	BytesTransferred int64 `json:"bytes_transferred"`
+}
+
+// Defines the information used to compare a path in the source and the destination.
+type syncEntry struct {
+	dir     bool
+	size    int64
+	perm    fs.FileMode
+	modTime time.Time
+}
+
+// Defines the side of the sync which is written to.
+type syncTarget interface {
+	mkdir(name string, perm fs.FileMode) error
+	removeAll(name string) error
+	write(name string, r io.Reader, perm fs.FileMode, modTime time.Time) error
+}
+
+// Walks the filesystem and returns all the directories and regular files. Anything else is skipped.
+func listSyncEntries(fsys fs.FS) (map[string]syncEntry, error) {
+	entries := map[string]syncEntry{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		entries[p] = syncEntry{
+			dir:     info.IsDir(),
+			size:    info.Size(),
+			perm:    info.Mode().Perm(),
+			modTime: info.ModTime(),
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// Defines the state of a running sync.
+type volumeSync struct {
+	ctx    context.Context
+	src    fs.FS
+	target syncTarget
+	opts   VolumeSyncOptions
+
+	mu     sync.Mutex
+	report VolumeSyncReport
+}
+
+// Records the item in the report and calls the progress function.
+func (s *volumeSync) record(item VolumeSyncItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report.Items = append(s.report.Items, item)
+	if item.Action == VolumeSyncActionTransfer && item.Err == nil {
+		s.report.BytesTransferred += item.Size
+	}
+	if s.opts.Progress != nil {
+		s.opts.Progress(item)
+	}
+}
+
+// Runs the action unless this is a dry run and records the result.
+func (s *volumeSync) apply(item VolumeSyncItem, fn func() error) error {
+	if !s.opts.DryRun {
+		if item.Err = s.ctx.Err(); item.Err == nil {
+			item.Err = fn()
+		}
+	}
+	s.record(item)
+	return item.Err
+}
+
+// Copies the file from the source to the target.
+func (s *volumeSync) transfer(name string, e syncEntry) error {
+	return s.apply(VolumeSyncItem{Path: name, Action: VolumeSyncActionTransfer, Size: e.size}, func() error {
+		f, err := s.src.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return s.target.write(name, f, e.perm, e.modTime)
+	})
+}
+
+// Syncs the source to the target. The function specified decides if a file in the source needs transferring.
+func (s *volumeSync) run(dst fs.FS, needsTransfer func(src, dst syncEntry) bool) (*VolumeSyncReport, error) {
+	if s.opts.Concurrency <= 0 {
+		s.opts.Concurrency = 4
+	}
+	srcEntries, err := listSyncEntries(s.src)
+	if err != nil {
+		return nil, err
+	}
+	dstEntries, err := listSyncEntries(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort the paths so parents always come before their children.
+	srcPaths := make([]string, 0, len(srcEntries))
+	for k := range srcEntries {
+		srcPaths = append(srcPaths, k)
+	}
+	sort.Strings(srcPaths)
+
+	// Work out what needs to be removed. This is anything which has the wrong type, and if deletion is on, anything
+	// which is not in the source. Only the top-most path is removed since removals are recursive.
+	var removals, mkdirs, transfers []string
+	for k, v := range dstEntries {
+		srcEntry, ok := srcEntries[k]
+		if (ok && srcEntry.dir != v.dir) || (!ok && s.opts.Delete) {
+			removals = append(removals, k)
+		}
+	}
+	sort.Strings(removals)
+	topRemovals := removals[:0]
+	for _, v := range removals {
+		if len(topRemovals) == 0 || !strings.HasPrefix(v, topRemovals[len(topRemovals)-1]+"/") {
+			topRemovals = append(topRemovals, v)
+		}
+	}
+	for _, k := range srcPaths {
+		srcEntry := srcEntries[k]
+		dstEntry, ok := dstEntries[k]
+		if ok && dstEntry.dir != srcEntry.dir {
+			// This was removed above.
+			ok = false
+		}
+		switch {
+		case srcEntry.dir && !ok:
+			mkdirs = append(mkdirs, k)
+		case srcEntry.dir:
+		case !ok || needsTransfer(srcEntry, dstEntry):
+			transfers = append(transfers, k)
+		default:
+			s.report.Unchanged++
+		}
+	}
+
+	// Do the removals and directories in order since everything after depends on them.
+	for _, k := range topRemovals {
+		name := k
+		err = s.apply(VolumeSyncItem{Path: name, Action: VolumeSyncActionDelete}, func() error {
+			return s.target.removeAll(name)
+		})
+		if err != nil {
+			return &s.report, err
+		}
+	}
+	for _, k := range mkdirs {
+		name := k
+		err = s.apply(VolumeSyncItem{Path: name, Action: VolumeSyncActionMkdir}, func() error {
+			return s.target.mkdir(name, srcEntries[name].perm)
+		})
+		if err != nil {
+			return &s.report, err
+		}
+	}
+
+	// Transfer the files with the concurrency limit.
+	eg := errgroup.Group{}
+	sem := make(chan struct{}, s.opts.Concurrency)
+	for _, k := range transfers {
+		name := k
+		sem <- struct{}{}
+		eg.Go(func() error {
+			defer func() { <-sem }()
+			return s.transfer(name, srcEntries[name])
+		})
+	}
+	return &s.report, eg.Wait()
+}
+
+// Defines a sync target which writes to a volume.
+type volumeSyncTarget struct {
+	f *WritableVolumeFS
+}
+
+func (t volumeSyncTarget) mkdir(name string, perm fs.FileMode) error { return t.f.Mkdir(name, perm) }
+
+func (t volumeSyncTarget) removeAll(name string) error { return t.f.RemoveAll(name) }
+
+func (t volumeSyncTarget) write(name string, r io.Reader, perm fs.FileMode, _ time.Time) error {
+	return t.f.Upload(name, r, perm)
+}
+
+// Defines a sync target which writes to a local directory.
+type localSyncTarget struct {
+	root string
+}
+
+func (t localSyncTarget) path(name string) string {
+	return filepath.Join(t.root, filepath.FromSlash(name))
+}
+
+func (t localSyncTarget) mkdir(name string, perm fs.FileMode) error {
+	return os.Mkdir(t.path(name), perm)
+}
+
+func (t localSyncTarget) removeAll(name string) error { return os.RemoveAll(t.path(name)) }
+
+// Writes to a temporary file and then moves it into place so a failed transfer does not leave a partial file.
+func (t localSyncTarget) write(name string, r io.Reader, perm fs.FileMode, modTime time.Time) error {
+	p := t.path(name)
+	f, err := os.CreateTemp(filepath.Dir(p), ".hop-sync-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(f.Name(), perm); err != nil {
+		return err
+	}
+	if err = os.Chtimes(f.Name(), modTime, modTime); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// SyncToVolume is used to mirror a local directory to a volume. A file is uploaded if it is missing from the volume,
+// has a different size, or was modified locally after the volume copy was last updated. Directories are created as
+// needed. If the Delete option is set, anything in the volume that is not in the local directory is removed.
+func (c ClientCategoryIgniteDeployments) SyncToVolume(
+	ctx context.Context, deploymentId, volumeId, localDir string, syncOpts VolumeSyncOptions, opts ...ClientOption,
+) (*VolumeSyncReport, error) {
+	f := c.WritableVolumeFS(ctx, deploymentId, volumeId, opts...)
+	s := &volumeSync{ctx: ctx, src: os.DirFS(localDir), target: volumeSyncTarget{f}, opts: syncOpts}
+	return s.run(f, func(src, dst syncEntry) bool {
+		return src.size != dst.size || src.modTime.After(dst.modTime)
+	})
+}
+
+// SyncFromVolume is used to mirror a volume to a local directory, creating the directory if it does not exist. A file
+// is downloaded if it is missing locally, has a different size, or has a different modification time to the volume
+// copy. The modification time of downloaded files is set to the time from the volume. If the Delete option is set,
+// anything in the local directory that is not in the volume is removed.
+func (c ClientCategoryIgniteDeployments) SyncFromVolume(
+	ctx context.Context, deploymentId, volumeId, localDir string, syncOpts VolumeSyncOptions, opts ...ClientOption,
+) (*VolumeSyncReport, error) {
+	var dst fs.FS = os.DirFS(localDir)
+	if !syncOpts.DryRun {
+		if err := os.MkdirAll(localDir, 0o755); err != nil {
+			return nil, err
+		}
+	} else if _, err := os.Stat(localDir); errors.Is(err, fs.ErrNotExist) {
+		// Compare against an empty directory since nothing exists yet.
+		dst = emptyFS{}
+	}
+	s := &volumeSync{
+		ctx:    ctx,
+		src:    c.VolumeVirtualFS(ctx, deploymentId, volumeId, opts...),
+		target: localSyncTarget{localDir},
+		opts:   syncOpts,
+	}
+	return s.run(dst, func(src, dst syncEntry) bool {
+		return src.size != dst.size || !src.modTime.Equal(dst.modTime)
+	})
+}
+
+// Defines a filesystem with an empty root directory.
+type emptyFS struct{}
+
+func (emptyFS) Open(name string) (fs.File, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return emptyDir{}, nil
+}
+
+type emptyDir struct{}
+
+func (emptyDir) Stat() (fs.FileInfo, error) {
+	return volumeFileInfo{&dirItem{Name: ".", Directory: true, Permissions: 0o755}}, nil
+}
+
+func (emptyDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: ".", Err: errors.New("is a directory")}
+}
+
+func (emptyDir) ReadDir(int) ([]fs.DirEntry, error) { return nil, nil }
+
+func (emptyDir) Close() error { return nil }
+
+// BackupVolume is used to write a gzipped tarball of everything in a volume to the writer specified.
+func (c ClientCategoryIgniteDeployments) BackupVolume(
+	ctx context.Context, deploymentId, volumeId string, w io.Writer, opts ...ClientOption,
+) error {
+	fsys := c.VolumeVirtualFS(ctx, deploymentId, volumeId, opts...)
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = p
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		_ = f.Close()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
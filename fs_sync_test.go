@@ -0,0 +1,186 @@
+package hop
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/internal/volumetest"
+)
+
+// Returns the items sorted by path so concurrent transfers can be compared.
+func sortedSyncItems(items []VolumeSyncItem) []VolumeSyncItem {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Path < items[j].Path })
+	return items
+}
+
+func newFakeVolumeDeployments(t *testing.T) (*volumetest.Server, ClientCategoryIgniteDeployments) {
+	t.Helper()
+	s, f := newFakeVolume(t)
+	return s, ClientCategoryIgniteDeployments{c: f.c}
+}
+
+func TestClient_Ignite_Deployments_SyncToVolume(t *testing.T) {
+	s, d := newFakeVolumeDeployments(t)
+	s.AddFile("unchanged.txt", "same", 0o644)
+	s.AddFile("changed.txt", "old", 0o644)
+	s.AddFile("extra/file.txt", "x", 0o644)
+	s.AddFile("typechange/inner.txt", "x", 0o644)
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"unchanged.txt":  "same",
+		"changed.txt":    "new content",
+		"new/nested.txt": "nested",
+		"typechange":     "now a file",
+	})
+
+	// Make the unchanged file older than the volume copy.
+	old := s.Now.Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "unchanged.txt"), old, old))
+
+	tests := []struct {
+		name string
+
+		opts      VolumeSyncOptions
+		wantItems []VolumeSyncItem
+	}{
+		{
+			name: "dry run",
+			opts: VolumeSyncOptions{DryRun: true, Delete: true},
+			wantItems: []VolumeSyncItem{
+				{Path: "changed.txt", Action: VolumeSyncActionTransfer, Size: 11},
+				{Path: "extra", Action: VolumeSyncActionDelete},
+				{Path: "new", Action: VolumeSyncActionMkdir},
+				{Path: "new/nested.txt", Action: VolumeSyncActionTransfer, Size: 6},
+				{Path: "typechange", Action: VolumeSyncActionDelete},
+				{Path: "typechange", Action: VolumeSyncActionTransfer, Size: 10},
+			},
+		},
+		{
+			name: "sync",
+			opts: VolumeSyncOptions{Delete: true, Concurrency: 1},
+			wantItems: []VolumeSyncItem{
+				{Path: "changed.txt", Action: VolumeSyncActionTransfer, Size: 11},
+				{Path: "extra", Action: VolumeSyncActionDelete},
+				{Path: "new", Action: VolumeSyncActionMkdir},
+				{Path: "new/nested.txt", Action: VolumeSyncActionTransfer, Size: 6},
+				{Path: "typechange", Action: VolumeSyncActionDelete},
+				{Path: "typechange", Action: VolumeSyncActionTransfer, Size: 10},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var progress []VolumeSyncItem
+			tt.opts.Progress = func(item VolumeSyncItem) { progress = append(progress, item) }
+			report, err := d.SyncToVolume(context.Background(), "test", "vol", dir, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantItems, sortedSyncItems(report.Items))
+			assert.Equal(t, tt.wantItems, sortedSyncItems(progress))
+			assert.Equal(t, 1, report.Unchanged)
+			if tt.opts.DryRun {
+				assert.Equal(t, int64(27), report.BytesTransferred)
+				content, _ := s.ReadFile("changed.txt")
+				assert.Equal(t, "old", content)
+				return
+			}
+			assert.Equal(t, []string{
+				".", "changed.txt", "new", "new/nested.txt", "typechange", "unchanged.txt",
+			}, s.Paths())
+			content, _ := s.ReadFile("changed.txt")
+			assert.Equal(t, "new content", content)
+			content, _ = s.ReadFile("typechange")
+			assert.Equal(t, "now a file", content)
+		})
+	}
+}
+
+func TestClient_Ignite_Deployments_SyncFromVolume(t *testing.T) {
+	s, d := newFakeVolumeDeployments(t)
+	s.AddFile("a.txt", "aaa", 0o600)
+	s.AddFile("dir/b.txt", "bbbb", 0o644)
+
+	dir := filepath.Join(t.TempDir(), "out")
+
+	// A dry run against a missing directory should not create it.
+	report, err := d.SyncFromVolume(context.Background(), "test", "vol", dir, VolumeSyncOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, report.Items, 3)
+	_, err = os.Stat(dir)
+	assert.True(t, os.IsNotExist(err))
+
+	var mu sync.Mutex
+	calls := 0
+	report, err = d.SyncFromVolume(context.Background(), "test", "vol", dir, VolumeSyncOptions{
+		Progress: func(VolumeSyncItem) {
+			mu.Lock()
+			calls++
+			mu.Unlock()
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []VolumeSyncItem{
+		{Path: "a.txt", Action: VolumeSyncActionTransfer, Size: 3},
+		{Path: "dir", Action: VolumeSyncActionMkdir},
+		{Path: "dir/b.txt", Action: VolumeSyncActionTransfer, Size: 4},
+	}, sortedSyncItems(report.Items))
+	assert.Equal(t, int64(7), report.BytesTransferred)
+	assert.Equal(t, 3, calls)
+
+	b, err := os.ReadFile(filepath.Join(dir, "dir", "b.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "bbbb", string(b))
+	info, err := os.Stat(filepath.Join(dir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	assert.Equal(t, s.Now, info.ModTime().UTC())
+
+	// A second sync should have nothing to do, and a local extra file should be removed.
+	writeTestFiles(t, dir, map[string]string{"extra.txt": "x"})
+	report, err = d.SyncFromVolume(context.Background(), "test", "vol", dir, VolumeSyncOptions{Delete: true})
+	require.NoError(t, err)
+	assert.Equal(t, []VolumeSyncItem{{Path: "extra.txt", Action: VolumeSyncActionDelete}}, report.Items)
+	assert.Equal(t, 2, report.Unchanged)
+	_, err = os.Stat(filepath.Join(dir, "extra.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestClient_Ignite_Deployments_BackupVolume(t *testing.T) {
+	s, d := newFakeVolumeDeployments(t)
+	s.AddFile("a.txt", "aaa", 0o600)
+	s.AddFile("dir/b.txt", "bbbb", 0o644)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, d.BackupVolume(context.Background(), "test", "vol", buf))
+
+	gz, err := gzip.NewReader(buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	got := map[string]string{}
+	for {
+		hdr, nextErr := tr.Next()
+		if nextErr == io.EOF {
+			break
+		}
+		require.NoError(t, nextErr)
+		b, readErr := io.ReadAll(tr)
+		require.NoError(t, readErr)
+		got[hdr.Name] = string(b)
+		if hdr.Name == "a.txt" {
+			assert.Equal(t, int64(0o600), hdr.Mode&0o777)
+			assert.Equal(t, s.Now, hdr.ModTime.UTC())
+		}
+	}
+	assert.Equal(t, map[string]string{"a.txt": "aaa", "dir/": "", "dir/b.txt": "bbbb"}, got)
+}
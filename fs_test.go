@@ -0,0 +1,340 @@
+package hop
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/internal/volumetest"
+)
+
+// Gets a file from the fake volume, failing the test if it does not exist.
+func statFake(t *testing.T, s *volumetest.Server, name string) volumetest.File {
+	t.Helper()
+	f, ok := s.Stat(name)
+	require.True(t, ok, name)
+	return f
+}
+
+// Makes a fake volume server and a writable filesystem pointing at it.
+func newFakeVolume(t *testing.T) (*volumetest.Server, *WritableVolumeFS) {
+	t.Helper()
+	s := volumetest.NewServer()
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	c, err := NewClient("pat_test", WithCustomAPIURL(srv.URL))
+	require.NoError(t, err)
+	return s, c.Ignite.Deployments.WritableVolumeFS(context.Background(), "test", "vol")
+}
+
+func TestWritableVolumeFS_WriteFile(t *testing.T) {
+	s, f := newFakeVolume(t)
+	require.NoError(t, f.WriteFile("hello.txt", []byte("hello world"), 0o600))
+	content, ok := s.ReadFile("hello.txt")
+	assert.True(t, ok)
+	assert.Equal(t, "hello world", content)
+	assert.Equal(t, 0o600, statFake(t, s, "hello.txt").Perm)
+
+	// Reads should go through the fs.FS implementation.
+	b, err := fs.ReadFile(f, "hello.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+
+	err = f.WriteFile("missing/hello.txt", []byte("x"), 0o644)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	var pathErr *fs.PathError
+	require.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, "open", pathErr.Op)
+	assert.Equal(t, "missing/hello.txt", pathErr.Path)
+
+	assert.ErrorIs(t, f.WriteFile("../escape", nil, 0o644), fs.ErrInvalid)
+}
+
+func TestWritableVolumeFS_Create(t *testing.T) {
+	s, f := newFakeVolume(t)
+	w, err := f.Create("streamed.txt")
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err = io.WriteString(w, "chunk "+strconv.Itoa(i)+"\n")
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	content, _ := s.ReadFile("streamed.txt")
+	assert.Equal(t, "chunk 0\nchunk 1\nchunk 2\n", content)
+	assert.Equal(t, 0o644, statFake(t, s, "streamed.txt").Perm)
+
+	// Errors from the API are returned from Close.
+	w, err = f.Create("missing/streamed.txt")
+	require.NoError(t, err)
+	_, _ = io.WriteString(w, "hello")
+	assert.ErrorIs(t, w.Close(), fs.ErrNotExist)
+}
+
+func TestWritableVolumeFS_Mkdir(t *testing.T) {
+	s, f := newFakeVolume(t)
+	s.AddFile("file", "x", 0o644)
+
+	require.NoError(t, f.Mkdir("a", 0o700))
+	assert.True(t, statFake(t, s, "a").Dir)
+	assert.Equal(t, 0o700, statFake(t, s, "a").Perm)
+	assert.ErrorIs(t, f.Mkdir("a", 0o700), fs.ErrExist)
+	assert.ErrorIs(t, f.Mkdir("b/c", 0o700), fs.ErrNotExist)
+
+	require.NoError(t, f.MkdirAll("a/b/c", 0o755))
+	require.NoError(t, f.MkdirAll("a/b/c", 0o755))
+	assert.True(t, statFake(t, s, "a/b/c").Dir)
+	assert.NoError(t, f.MkdirAll(".", 0o755))
+	assert.ErrorIs(t, f.MkdirAll("file/x", 0o755), ErrNotADirectory)
+}
+
+func TestWritableVolumeFS_Remove(t *testing.T) {
+	s, f := newFakeVolume(t)
+	s.AddFile("a/b/c.txt", "x", 0o644)
+	s.AddFile("d.txt", "x", 0o644)
+
+	require.NoError(t, f.Remove("d.txt"))
+	assert.NotContains(t, s.Paths(), "d.txt")
+	assert.ErrorIs(t, f.Remove("d.txt"), fs.ErrNotExist)
+	assert.ErrorIs(t, f.Remove("a"), ErrDirectoryNotEmpty)
+	assert.ErrorIs(t, f.Remove("."), fs.ErrInvalid)
+
+	require.NoError(t, f.RemoveAll("a"))
+	assert.Equal(t, []string{"."}, s.Paths())
+	assert.NoError(t, f.RemoveAll("a"))
+}
+
+func TestWritableVolumeFS_Rename(t *testing.T) {
+	s, f := newFakeVolume(t)
+	s.AddFile("a/b.txt", "hello", 0o644)
+	require.NoError(t, f.Mkdir("c", 0o755))
+
+	require.NoError(t, f.Rename("a", "c/a"))
+	content, ok := s.ReadFile("c/a/b.txt")
+	assert.True(t, ok)
+	assert.Equal(t, "hello", content)
+	assert.NotContains(t, s.Paths(), "a")
+	assert.ErrorIs(t, f.Rename("missing", "x"), fs.ErrNotExist)
+	assert.ErrorIs(t, f.Rename("c", "missing/c"), fs.ErrNotExist)
+}
+
+func TestWritableVolumeFS_Chmod(t *testing.T) {
+	s, f := newFakeVolume(t)
+	s.AddFile("run.sh", "#!/bin/sh", 0o644)
+	require.NoError(t, f.Chmod("run.sh", fs.ModeSetuid|0o755))
+	assert.Equal(t, 0o755, statFake(t, s, "run.sh").Perm)
+	assert.ErrorIs(t, f.Chmod("missing", 0o755), fs.ErrNotExist)
+}
+
+// Makes a fake volume with a few files and the read-only filesystem pointing at it.
+func newFakeVolumeVirtualFS(t *testing.T) (*volumetest.Server, fs.FS) {
+	t.Helper()
+	s, f := newFakeVolume(t)
+	s.AddFile("README.md", "# hello", 0o644)
+	s.AddFile("bin/run.sh", "#!/bin/sh\necho hi\n", 0o755)
+	s.AddFile("data/a/1.json", "{}", 0o600)
+	s.AddFile("data/a/2.json", "[]", 0o600)
+	s.AddFile("data/b.txt", "bbb", 0o640)
+	s.SetPerm("data", 0o700)
+	return s, f.volumeFs
+}
+
+func TestVolumeVirtualFS_TestFS(t *testing.T) {
+	_, f := newFakeVolumeVirtualFS(t)
+	assert.NoError(t, fstest.TestFS(f, "README.md", "bin/run.sh", "data/a/1.json", "data/a/2.json", "data/b.txt"))
+}
+
+func TestVolumeVirtualFS_Stat(t *testing.T) {
+	s, f := newFakeVolumeVirtualFS(t)
+	tests := []struct {
+		name string
+
+		wantName string
+		wantMode fs.FileMode
+		wantSize int64
+		wantErr  error
+	}{
+		{name: "README.md", wantName: "README.md", wantMode: 0o644, wantSize: 7},
+		{name: "bin/run.sh", wantName: "run.sh", wantMode: 0o755, wantSize: 18},
+		{name: "data", wantName: "data", wantMode: fs.ModeDir | 0o700},
+		{name: "data/a", wantName: "a", wantMode: fs.ModeDir | 0o755},
+		{name: ".", wantName: ".", wantMode: fs.ModeDir | 0o777},
+		{name: "missing", wantErr: fs.ErrNotExist},
+		{name: "../x", wantErr: fs.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := fs.Stat(f, tt.name)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, info.Name())
+			assert.Equal(t, tt.wantMode, info.Mode())
+			assert.Equal(t, tt.wantMode.IsDir(), info.IsDir())
+			if !info.IsDir() {
+				assert.Equal(t, tt.wantSize, info.Size())
+			}
+			assert.Equal(t, s.Now, info.ModTime().UTC())
+		})
+	}
+}
+
+func TestVolumeVirtualFS_ReadDir(t *testing.T) {
+	_, f := newFakeVolumeVirtualFS(t)
+	entries, err := fs.ReadDir(f, "data")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "a", entries[0].Name())
+	assert.True(t, entries[0].IsDir())
+	assert.Equal(t, fs.ModeDir, entries[0].Type())
+	assert.Equal(t, "b.txt", entries[1].Name())
+	assert.False(t, entries[1].IsDir())
+
+	// Read the directory in chunks.
+	file, err := f.Open(".")
+	require.NoError(t, err)
+	defer file.Close()
+	dir, ok := file.(fs.ReadDirFile)
+	require.True(t, ok)
+	var names []string
+	for {
+		chunk, readErr := dir.ReadDir(2)
+		if readErr == io.EOF {
+			break
+		}
+		require.NoError(t, readErr)
+		for _, v := range chunk {
+			names = append(names, v.Name())
+		}
+	}
+	assert.Equal(t, []string{"README.md", "bin", "data"}, names)
+
+	_, err = fs.ReadDir(f, "README.md")
+	assert.ErrorIs(t, err, ErrNotADirectory)
+}
+
+func TestVolumeVirtualFS_Sub(t *testing.T) {
+	_, f := newFakeVolumeVirtualFS(t)
+	sub, err := fs.Sub(f, "data")
+	require.NoError(t, err)
+	b, err := fs.ReadFile(sub, "a/2.json")
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(b))
+	assert.NoError(t, fstest.TestFS(sub, "a/1.json", "a/2.json", "b.txt"))
+
+	matches, err := fs.Glob(f, "data/a/*.json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data/a/1.json", "data/a/2.json"}, matches)
+
+	_, err = fs.Sub(f, "README.md")
+	assert.ErrorIs(t, err, ErrNotADirectory)
+}
+
+func TestVolumeVirtualFS_ReadAt(t *testing.T) {
+	s, f := newFakeVolume(t)
+	s.AddFile("log.txt", "0123456789", 0o644)
+	file, err := f.Open("log.txt")
+	require.NoError(t, err)
+	defer file.Close()
+	ra, ok := file.(io.ReaderAt)
+	require.True(t, ok)
+
+	tests := []struct {
+		name string
+
+		off     int64
+		n       int
+		want    string
+		wantErr error
+	}{
+		{name: "middle", off: 2, n: 3, want: "234"},
+		{name: "tail", off: 7, n: 3, want: "789"},
+		{name: "past the end", off: 8, n: 5, want: "89", wantErr: io.EOF},
+		{name: "at the end", off: 10, n: 1, wantErr: io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := make([]byte, tt.n)
+			n, readErr := ra.ReadAt(b, tt.off)
+			assert.Equal(t, tt.wantErr, readErr)
+			assert.Equal(t, tt.want, string(b[:n]))
+		})
+	}
+}
+
+func TestVolumeVirtualFS_Seek(t *testing.T) {
+	s, f := newFakeVolume(t)
+	s.AddFile("log.txt", "0123456789", 0o644)
+	file, err := f.Open("log.txt")
+	require.NoError(t, err)
+	defer file.Close()
+	rs, ok := file.(io.ReadSeeker)
+	require.True(t, ok)
+
+	b := make([]byte, 2)
+	_, err = io.ReadFull(rs, b)
+	require.NoError(t, err)
+	assert.Equal(t, "01", string(b))
+
+	off, err := rs.Seek(-3, io.SeekEnd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), off)
+	rest, err := io.ReadAll(rs)
+	require.NoError(t, err)
+	assert.Equal(t, "789", string(rest))
+
+	off, err = rs.Seek(-5, io.SeekCurrent)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), off)
+	_, err = io.ReadFull(rs, b)
+	require.NoError(t, err)
+	assert.Equal(t, "56", string(b))
+
+	_, err = rs.Seek(-1, io.SeekStart)
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
+
+func TestVolumeVirtualFS_ServeContent(t *testing.T) {
+	s, f := newFakeVolume(t)
+	s.AddFile("log.txt", "0123456789", 0o644)
+	file, err := f.Open("log.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	rs, ok := file.(io.ReadSeeker)
+	require.True(t, ok)
+	req := httptest.NewRequest("GET", "/log.txt", nil)
+	req.Header.Set("Range", "bytes=-4")
+	rec := httptest.NewRecorder()
+	http.ServeContent(rec, req, "log.txt", s.Now, rs)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "6789", rec.Body.String())
+}
+
+func TestVolumeVirtualFS_RangeNotSupported(t *testing.T) {
+	s, f := newFakeVolume(t)
+	s.AddFile("log.txt", "0123456789", 0o644)
+	s.NoRanges = true
+	file, err := f.Open("log.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	ra, ok := file.(io.ReaderAt)
+	require.True(t, ok)
+	_, err = ra.ReadAt(make([]byte, 2), 4)
+	assert.ErrorIs(t, err, ErrRangeNotSupported)
+
+	// Reading from the start does not need a range.
+	b, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(b))
+}
@@ -0,0 +1,209 @@
+// Package healthcheck is used to run Ignite health checks locally. This allows health check configurations to be
+// validated against a container running on your machine before they are deployed, since a misconfigured health check
+// otherwise only shows up as a failed rollout.
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.hop.io/sdk"
+	"go.hop.io/sdk/types"
+)
+
+// Config is used to define where and how the health check is run.
+type Config struct {
+	// Target is where the health check is run against. This can either be a host such as "localhost", in which case the
+	// port and path of the health check are used, or a base URL such as "http://localhost:3000", in which case only
+	// the path of the health check is used. If this is blank, "localhost" is used.
+	Target string
+
+	// Type is the type of the health check. If this is blank, it will default to liveness.
+	Type types.HealthCheckType
+
+	// MaxProbes is the number of probes to make before Run returns. If this is 0, Run will continue until the context
+	// is cancelled.
+	MaxProbes int
+
+	// Client is the HTTP client used to make probes. If this is nil, a client which does not follow redirects is used.
+	Client *http.Client
+
+	// Clock is the clock used to wait between probes. If this is nil, the system clock is used.
+	Clock hop.Clock
+}
+
+// Transition is used to define a change in the status of the health check.
+type Transition struct {
+	// From is the status before the probe.
+	From types.HealthCheckStatus
+
+	// To is the status after the probe.
+	To types.HealthCheckStatus
+
+	// At is when the probe finished.
+	At time.Time
+
+	// Probe is the number of the probe which caused the transition, starting at 1.
+	Probe int
+
+	// Err is the error from the probe. This is nil if the probe succeeded.
+	Err error
+
+	// Restart is true if a liveness health check failed, in which case Ignite would restart the container. The runner
+	// waits for the initial delay again after this.
+	Restart bool
+}
+
+// Runner is used to run a health check locally with the same semantics as Ignite. Please use New or FromHealthCheck
+// to create this.
+type Runner struct {
+	opts   types.HealthCheckCreateOpts
+	cfg    Config
+	url    string
+	status types.HealthCheckStatus
+}
+
+// Returns the URL which is probed.
+func probeURL(target string, opts types.HealthCheckCreateOpts) (string, error) {
+	if target == "" {
+		target = "localhost"
+	}
+	path := opts.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(u.String(), "/") + path, nil
+	}
+	return "http://" + target + ":" + strconv.Itoa(opts.Port) + path, nil
+}
+
+// New is used to create a runner for the health check options specified. The same defaults as the API are applied to
+// any blank options.
+func New(opts types.HealthCheckCreateOpts, cfg Config) (*Runner, error) {
+	opts = opts.WithDefaults()
+	if opts.Protocol != types.HealthCheckProtocolHTTP {
+		return nil, errors.New("unsupported health check protocol: " + string(opts.Protocol))
+	}
+	if opts.MaxRetries < 0 {
+		return nil, errors.New("max retries must not be negative")
+	}
+	u, err := probeURL(cfg.Target, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Type == "" {
+		cfg.Type = types.HealthCheckTypeLiveness
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = hop.SystemClock{}
+	}
+	return &Runner{opts: opts, cfg: cfg, url: u, status: types.HealthCheckStatusPending}, nil
+}
+
+// FromHealthCheck is used to create a runner for a health check which was fetched from the API. The type of the
+// health check is used over the type in the config.
+func FromHealthCheck(hc *types.HealthCheck, cfg Config) (*Runner, error) {
+	if hc.Type != "" {
+		cfg.Type = hc.Type
+	}
+	return New(hc.HealthCheckCreateOpts, cfg)
+}
+
+// Options returns the health check options with the defaults applied.
+func (r *Runner) Options() types.HealthCheckCreateOpts { return r.opts }
+
+// URL returns the URL which is probed.
+func (r *Runner) URL() string { return r.url }
+
+// Probe is used to make a single request to the health check URL. A response with a status code from 200 to 399 is
+// treated as a success. The request is cancelled after the health check timeout.
+func (r *Runner) Probe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(r.opts.Timeout))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", r.url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	res, err := r.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 400 {
+		return errors.New("health check returned status code " + strconv.Itoa(res.StatusCode))
+	}
+	return nil
+}
+
+// Waits for the duration specified. Returns the context error if the context is cancelled first.
+func (r *Runner) wait(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.cfg.Clock.After(d):
+		return nil
+	}
+}
+
+// Run is used to run the health check until the context is cancelled or the maximum number of probes is reached,
+// calling the function specified on every status change. The runner waits for the initial delay and then probes on
+// every interval. The status starts as pending, becomes succeeded after a successful probe, and becomes failed when
+// a probe and all of the retries after it fail. Liveness checks go back to pending after they fail, since the
+// container would be restarted. Returns nil if the maximum number of probes was reached.
+func (r *Runner) Run(ctx context.Context, fn func(Transition)) error {
+	failures := 0
+	delay := time.Duration(r.opts.InitialDelay)
+	for probe := 1; r.cfg.MaxProbes == 0 || probe <= r.cfg.MaxProbes; probe++ {
+		if err := r.wait(ctx, delay); err != nil {
+			return err
+		}
+		delay = time.Duration(r.opts.Interval)
+
+		err := r.Probe(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		t := Transition{From: r.status, At: r.cfg.Clock.Now(), Probe: probe, Err: err}
+		if err == nil {
+			failures = 0
+			t.To = types.HealthCheckStatusSucceeded
+		} else {
+			failures++
+			if failures <= r.opts.MaxRetries {
+				// Retries do not change the status.
+				continue
+			}
+			failures = 0
+			t.To = types.HealthCheckStatusFailed
+			if r.cfg.Type == types.HealthCheckTypeLiveness {
+				t.Restart = true
+				delay = time.Duration(r.opts.InitialDelay)
+			}
+		}
+
+		if t.To != t.From || t.Restart {
+			fn(t)
+		}
+		r.status = t.To
+		if t.Restart {
+			r.status = types.HealthCheckStatusPending
+		}
+	}
+	return nil
+}
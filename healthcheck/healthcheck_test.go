@@ -0,0 +1,217 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk"
+	"go.hop.io/sdk/types"
+)
+
+// Defines a clock which returns immediately and records the durations waited for.
+type instantClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	waited []time.Duration
+}
+
+func (c *instantClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *instantClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.waited = append(c.waited, d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Returns a server which responds with the status codes specified in order, repeating the last one.
+func newStatusServer(t *testing.T, codes ...int) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		mu.Lock()
+		code := codes[0]
+		if len(codes) > 1 {
+			codes = codes[1:]
+		}
+		mu.Unlock()
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+
+		opts    types.HealthCheckCreateOpts
+		target  string
+		wantURL string
+		wantErr error
+	}{
+		{name: "defaults", wantURL: "http://localhost:8080/"},
+		{
+			name:    "host",
+			opts:    types.HealthCheckCreateOpts{Path: "health", Port: 3000},
+			target:  "127.0.0.1",
+			wantURL: "http://127.0.0.1:3000/health",
+		},
+		{
+			name:    "url",
+			opts:    types.HealthCheckCreateOpts{Path: "/health", Port: 3000},
+			target:  "https://example.com/",
+			wantURL: "https://example.com/health",
+		},
+		{
+			name:    "unsupported protocol",
+			opts:    types.HealthCheckCreateOpts{Protocol: "tcp"},
+			wantErr: errors.New("unsupported health check protocol: tcp"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.opts, Config{Target: tt.target})
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				assert.Equal(t, tt.wantURL, r.URL())
+				assert.Equal(t, hop.SystemClock{}, r.cfg.Clock)
+			}
+		})
+	}
+}
+
+func TestRunner_Probe(t *testing.T) {
+	for code, wantErr := range map[int]error{
+		http.StatusOK:                  nil,
+		http.StatusFound:               nil,
+		http.StatusNotFound:            errors.New("health check returned status code 404"),
+		http.StatusInternalServerError: errors.New("health check returned status code 500"),
+	} {
+		srv := newStatusServer(t, code)
+		r, err := New(types.HealthCheckCreateOpts{Path: "/health"}, Config{Target: srv.URL})
+		require.NoError(t, err)
+		assert.Equal(t, wantErr, r.Probe(context.Background()))
+	}
+}
+
+func TestRunner_Probe_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	r, err := New(types.HealthCheckCreateOpts{Timeout: types.MillisecondsFromInt(10)}, Config{Target: srv.URL})
+	require.NoError(t, err)
+	assert.ErrorIs(t, r.Probe(context.Background()), context.DeadlineExceeded)
+}
+
+func TestRunner_Run(t *testing.T) {
+	fail := http.StatusServiceUnavailable
+	tests := []struct {
+		name string
+
+		checkType types.HealthCheckType
+		codes     []int
+		probes    int
+		want      []Transition
+		wantWaits []time.Duration
+	}{
+		{
+			name:      "succeeds",
+			checkType: types.HealthCheckTypeReadiness,
+			codes:     []int{200},
+			probes:    3,
+			want:      []Transition{{From: "pending", To: "succeeded", Probe: 1}},
+			wantWaits: []time.Duration{5 * time.Second, time.Minute, time.Minute},
+		},
+		{
+			name:      "readiness fails after retries",
+			checkType: types.HealthCheckTypeReadiness,
+			codes:     []int{200, fail, fail, fail, fail, 200},
+			probes:    6,
+			want: []Transition{
+				{From: "pending", To: "succeeded", Probe: 1},
+				{From: "succeeded", To: "failed", Probe: 5},
+				{From: "failed", To: "succeeded", Probe: 6},
+			},
+			wantWaits: []time.Duration{5 * time.Second, time.Minute, time.Minute, time.Minute, time.Minute, time.Minute},
+		},
+		{
+			name:      "readiness recovers within retries",
+			checkType: types.HealthCheckTypeReadiness,
+			codes:     []int{200, fail, fail, 200},
+			probes:    4,
+			want:      []Transition{{From: "pending", To: "succeeded", Probe: 1}},
+		},
+		{
+			name:      "liveness restarts",
+			checkType: types.HealthCheckTypeLiveness,
+			codes:     []int{fail, fail, fail, fail, 200},
+			probes:    5,
+			want: []Transition{
+				{From: "pending", To: "failed", Probe: 4, Restart: true},
+				{From: "pending", To: "succeeded", Probe: 5},
+			},
+			wantWaits: []time.Duration{5 * time.Second, time.Minute, time.Minute, time.Minute, 5 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newStatusServer(t, tt.codes...)
+			clock := &instantClock{}
+			r, err := New(types.HealthCheckCreateOpts{Path: "/health"}, Config{
+				Target:    srv.URL,
+				Type:      tt.checkType,
+				MaxProbes: tt.probes,
+				Clock:     clock,
+			})
+			require.NoError(t, err)
+
+			var got []Transition
+			require.NoError(t, r.Run(context.Background(), func(tr Transition) {
+				assert.Equal(t, clock.Now(), tr.At)
+				assert.Equal(t, tr.To == types.HealthCheckStatusFailed, tr.Err != nil)
+				tr.At = time.Time{}
+				tr.Err = nil
+				got = append(got, tr)
+			}))
+			assert.Equal(t, tt.want, got)
+			if tt.wantWaits != nil {
+				assert.Equal(t, tt.wantWaits, clock.waited)
+			}
+		})
+	}
+}
+
+func TestRunner_Run_Cancel(t *testing.T) {
+	srv := newStatusServer(t, http.StatusOK)
+	r, err := FromHealthCheck(&types.HealthCheck{
+		HealthCheckCreateOpts: types.HealthCheckCreateOpts{Path: "/health"},
+		Type:                  types.HealthCheckTypeReadiness,
+	}, Config{Target: srv.URL, Clock: &instantClock{}})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err = r.Run(ctx, func(Transition) {
+		calls++
+		cancel()
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, calls)
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,6 +40,10 @@ func (c *mockClientDoer) getProjectId(opts []ClientOption) string {
 
 func (c *mockClientDoer) getTokenType() string { return c.tokenType }
 
+func (c *mockClientDoer) dialWebsocket(context.Context, string, map[string]string, []ClientOption) (*websocket.Conn, error) {
+	return nil, errors.New("websockets are not supported by the mock client")
+}
+
 func (c *mockClientDoer) do(ctx context.Context, a ClientArgs, opts []ClientOption) error {
 	c.t.Helper()
 	assert.NotNil(c.t, ctx)
@@ -183,3 +188,29 @@ func errorForTokenType(m *mockClientDoer, obj any, funcName string, args []any,
 		assert.NotNil(t, errValue.Interface())
 	})
 }
+
+// funcClientDoer is used to mock the client for functions which make more than one request. Each request is passed
+// to fn in order.
+type funcClientDoer struct {
+	tokenType string
+	fn        func(a ClientArgs, opts []ClientOption) error
+}
+
+func (c *funcClientDoer) getProjectId(opts []ClientOption) string {
+	return (&mockClientDoer{}).getProjectId(opts)
+}
+
+func (c *funcClientDoer) getTokenType() string { return c.tokenType }
+
+func (c *funcClientDoer) dialWebsocket(context.Context, string, map[string]string, []ClientOption) (*websocket.Conn, error) {
+	return nil, errors.New("websockets are not supported by the mock client")
+}
+
+func (c *funcClientDoer) do(_ context.Context, a ClientArgs, opts []ClientOption) error {
+	return c.fn(a, opts)
+}
+
+// Sets the result of the client arguments to the value specified.
+func setResult(a ClientArgs, v any) {
+	reflect.ValueOf(a.Result).Elem().Set(reflect.ValueOf(v))
+}
@@ -9,13 +9,68 @@ import (
 )
 
 // AddDomain is used to add a domain to the gateway. The parameter gatewayId is the ID of the gateway to add the domain to,
-// and domain is the full name of the domain.
-func (c ClientCategoryIgniteGateways) AddDomain(ctx context.Context, gatewayId string, domain string, opts ...ClientOption) error {
-	return c.c.do(ctx, ClientArgs{
-		Method: "POST",
-		Path:   "/ignite/gateways/" + url.PathEscape(gatewayId) + "/domains",
-		Body:   map[string]any{"domain": domain},
+// and domain is the full name of the domain. The domain will be pending until the DNS records are set, so use
+// ExpectedDNSRecords to find out what they should be.
+func (c ClientCategoryIgniteGateways) AddDomain(
+	ctx context.Context, gatewayId string, domain string, opts ...ClientOption,
+) (*types.Domain, error) {
+	var d types.Domain
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "POST",
+		Path:      "/ignite/gateways/" + url.PathEscape(gatewayId) + "/domains",
+		Body:      map[string]any{"domain": domain},
+		ResultKey: "domain",
+		Result:    &d,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetAllDomains is used to get all the domains within a project.
+func (c ClientCategoryIgniteGateways) GetAllDomains(ctx context.Context, opts ...ClientOption) ([]*types.Domain, error) {
+	var a []*types.Domain
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      "/ignite/domains",
+		ResultKey: "domains",
+		Result:    &a,
 	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SetDomainRedirect is used to make a domain redirect to another URL. If the redirect is nil, the redirect is removed
+// and the domain will point to the gateway again.
+func (c ClientCategoryIgniteGateways) SetDomainRedirect(
+	ctx context.Context, domainId string, redirect *types.DomainRedirect, opts ...ClientOption,
+) (*types.Domain, error) {
+	if redirect != nil {
+		if redirect.URL == "" {
+			return nil, errors.New("redirect url must be specified")
+		}
+		switch redirect.StatusCode {
+		case 301, 302, 307, 308:
+		default:
+			return nil, errors.New("redirect status code must be 301, 302, 307 or 308")
+		}
+	}
+
+	var d types.Domain
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "PATCH",
+		Path:      "/ignite/domains/" + url.PathEscape(domainId),
+		Body:      map[string]*types.DomainRedirect{"redirect": redirect},
+		ResultKey: "domain",
+		Result:    &d,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
 }
 
 // GetDomain is used to get a domain by its ID.
@@ -66,10 +121,13 @@ func (c ClientCategoryIgniteGateways) Delete(ctx context.Context, id string, opt
 	}, opts)
 }
 
-// Update is used to update a gateway by its ID.
+// Update is used to update a gateway by its ID. The options are validated before the request is made.
 func (c ClientCategoryIgniteGateways) Update(
 	ctx context.Context, id string, updateOpts types.IgniteGatewayUpdateOpts, opts ...ClientOption,
 ) (*types.Gateway, error) {
+	if err := updateOpts.Validate(); err != nil {
+		return nil, err
+	}
 	var gw types.Gateway
 	err := c.c.do(ctx, ClientArgs{
 		Method:    "PATCH",
@@ -84,6 +142,13 @@ func (c ClientCategoryIgniteGateways) Update(
 	return &gw, nil
 }
 
+// SetHopshDomainEnabled is used to enable or disable the hop.sh domain of a gateway.
+func (c ClientCategoryIgniteGateways) SetHopshDomainEnabled(
+	ctx context.Context, id string, enabled bool, opts ...ClientOption,
+) (*types.Gateway, error) {
+	return c.Update(ctx, id, types.IgniteGatewayUpdateOpts{HopshDomainEnabled: &enabled}, opts...)
+}
+
 // Create is used to create a deployment.
 func (c ClientCategoryIgniteDeployments) Create(
 	ctx context.Context, deployment *types.DeploymentConfig, opts ...ClientOption,
@@ -232,10 +297,14 @@ func (c ClientCategoryIgniteDeployments) GetAllGateways(ctx context.Context, id
 	return gateways, nil
 }
 
-// CreateGateway is used to create a gateway attached to a deployment.
+// CreateGateway is used to create a gateway attached to a deployment. The options are validated before the request is
+// made.
 func (c ClientCategoryIgniteDeployments) CreateGateway(
 	ctx context.Context, opts types.GatewayCreationOptions, clientOpts ...ClientOption,
 ) (*types.Gateway, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 	if opts.ProjectID != "" { //nolint:staticcheck // Support deprecated field.
 		clientOpts = append(clientOpts, WithProjectID(opts.ProjectID)) //nolint:staticcheck // Support deprecated field.
 	}
@@ -311,21 +380,124 @@ func (c ClientCategoryIgniteContainers) Start(ctx context.Context, id string, op
 	return c.updateContainerState(ctx, id, types.ContainerStateRunning, opts)
 }
 
-// Create is used to create a container.
-func (c ClientCategoryIgniteContainers) Create(ctx context.Context, deploymentId string, opts ...ClientOption) (*types.Container, error) {
+// Used to create containers in a deployment.
+func (c ClientCategoryIgniteContainers) createContainers(
+	ctx context.Context, deploymentId string, body any, opts []ClientOption,
+) ([]*types.Container, error) {
 	var a []*types.Container
 	err := c.c.do(ctx, ClientArgs{
 		Method:    "POST",
 		Path:      "/ignite/deployments/" + url.PathEscape(deploymentId) + "/containers",
+		Body:      body,
 		ResultKey: "containers",
 		Result:    &a,
 	}, opts)
 	if err != nil {
 		return nil, err
 	}
+	return a, nil
+}
+
+// Create is used to create a container.
+func (c ClientCategoryIgniteContainers) Create(ctx context.Context, deploymentId string, opts ...ClientOption) (*types.Container, error) {
+	a, err := c.createContainers(ctx, deploymentId, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(a) == 0 {
+		return nil, errors.New("api response error: no containers were returned - please report this to " +
+			"the go-hop github repository")
+	}
 	return a[0], nil
 }
 
+// CreateMany is used to create the number of containers specified in a deployment. Returns all the containers that
+// were created.
+func (c ClientCategoryIgniteContainers) CreateMany(
+	ctx context.Context, deploymentId string, count uint, opts ...ClientOption,
+) ([]*types.Container, error) {
+	if count == 0 {
+		return nil, errors.New("container count must be greater than 0")
+	}
+	return c.createContainers(ctx, deploymentId, map[string]uint{"count": count}, opts)
+}
+
+// CreateWithOverrides is used to create a container in a deployment for each of the resource overrides specified.
+// Returns all the containers that were created in the same order as the overrides.
+func (c ClientCategoryIgniteContainers) CreateWithOverrides(
+	ctx context.Context, deploymentId string, overrides []types.Resources, opts ...ClientOption,
+) ([]*types.Container, error) {
+	if len(overrides) == 0 {
+		return nil, errors.New("at least one override must be specified")
+	}
+	return c.createContainers(ctx, deploymentId, map[string]any{
+		"count":     len(overrides),
+		"overrides": overrides,
+	}, opts)
+}
+
+// Get is used to get a container by its ID.
+func (c ClientCategoryIgniteContainers) Get(ctx context.Context, id string, opts ...ClientOption) (*types.Container, error) {
+	var container types.Container
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      "/ignite/containers/" + url.PathEscape(id),
+		ResultKey: "container",
+		Result:    &container,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &container, nil
+}
+
+// Returns the JSON body for container resource overrides. Only the fields which are set are included, so the API
+// uses the resources of the deployment for the rest. A nil value is sent as null, which clears the overrides.
+func resourceOverridesBody(r *types.Resources) any {
+	if r == nil {
+		return nil
+	}
+	m := map[string]any{}
+	if r.VCPU != 0 {
+		m["vcpu"] = r.VCPU
+	}
+	if r.RAM != "" {
+		m["ram"] = r.RAM
+	}
+	if r.VGPU != nil {
+		m["vgpu"] = r.VGPU
+	}
+	return m
+}
+
+// SetOverrides is used to manually override the resources of a container. Only the fields which are set are
+// overridden, and the rest use the resources of the deployment. If overrides is nil, the overrides are cleared.
+func (c ClientCategoryIgniteContainers) SetOverrides(
+	ctx context.Context, id string, overrides *types.Resources, opts ...ClientOption,
+) (*types.Container, error) {
+	var container types.Container
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "PATCH",
+		Path:      "/ignite/containers/" + url.PathEscape(id),
+		Body:      map[string]any{"overrides": resourceOverridesBody(overrides)},
+		ResultKey: "container",
+		Result:    &container,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &container, nil
+}
+
+// Restart is used to restart the process inside a container by its ID. Unlike DeleteAndRecreate, the container keeps
+// its ID and is not rescheduled.
+func (c ClientCategoryIgniteContainers) Restart(ctx context.Context, id string, opts ...ClientOption) error {
+	return c.c.do(ctx, ClientArgs{
+		Method: "POST",
+		Path:   "/ignite/containers/" + url.PathEscape(id) + "/restart",
+	}, opts)
+}
+
 // Scale is used to scale the container count of a deployment.
 func (c ClientCategoryIgniteDeployments) Scale(
 	ctx context.Context, deploymentId string, containerCount uint, opts ...ClientOption,
@@ -353,27 +525,7 @@ func (c ClientCategoryIgniteDeployments) NewHealthCheck(
 	createOpts.DeploymentID = ""
 
 	// Set the defaults.
-	if createOpts.Protocol == "" {
-		createOpts.Protocol = types.HealthCheckProtocolHTTP
-	}
-	if createOpts.Path == "" {
-		createOpts.Path = "/"
-	}
-	if createOpts.Port == 0 {
-		createOpts.Port = 8080
-	}
-	if createOpts.InitialDelay == 0 {
-		createOpts.InitialDelay = types.SecondsFromInt(5)
-	}
-	if createOpts.Interval == 0 {
-		createOpts.Interval = types.SecondsFromInt(60)
-	}
-	if createOpts.Timeout == 0 {
-		createOpts.Timeout = types.MillisecondsFromInt(50)
-	}
-	if createOpts.MaxRetries == 0 {
-		createOpts.MaxRetries = 3
-	}
+	createOpts = createOpts.WithDefaults()
 
 	// Do the HTTP request.
 	var res types.HealthCheck
@@ -469,3 +621,217 @@ func (c ClientCategoryIgniteDeployments) GetStorageStats(
 	}
 	return res, nil
 }
+
+// Returns the path of a volume on a deployment.
+func volumePath(deploymentId, volumeId string) string {
+	return "/ignite/deployments/" + url.PathEscape(deploymentId) + "/volumes/" + url.PathEscape(volumeId)
+}
+
+// ResizeVolume is used to resize a deployment volume. Volumes can only grow, and the resize is applied the next time
+// the container using the volume is started, so use GetVolumeResize to check if it has been applied.
+func (c ClientCategoryIgniteDeployments) ResizeVolume(
+	ctx context.Context, deploymentId, volumeId string, size types.Size, opts ...ClientOption,
+) (*types.VolumeResize, error) {
+	if _, err := size.Bytes(); err != nil {
+		return nil, errors.New("invalid volume size: " + string(size))
+	}
+	var res types.VolumeResize
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "POST",
+		Path:      volumePath(deploymentId, volumeId) + "/resize",
+		Body:      map[string]types.Size{"size": size},
+		ResultKey: "resize",
+		Result:    &res,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetVolumeResize is used to get the latest resize of a deployment volume. Returns a types.NotFound error if the
+// volume has never been resized.
+func (c ClientCategoryIgniteDeployments) GetVolumeResize(
+	ctx context.Context, deploymentId, volumeId string, opts ...ClientOption,
+) (*types.VolumeResize, error) {
+	var res types.VolumeResize
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      volumePath(deploymentId, volumeId) + "/resize",
+		ResultKey: "resize",
+		Result:    &res,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// VolumeResizePending is used to check if a deployment volume has a resize which has not been applied yet.
+func (c ClientCategoryIgniteDeployments) VolumeResizePending(
+	ctx context.Context, deploymentId, volumeId string, opts ...ClientOption,
+) (bool, error) {
+	res, err := c.GetVolumeResize(ctx, deploymentId, volumeId, opts...)
+	if err != nil {
+		if _, ok := err.(types.NotFound); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	return res.Pending(), nil
+}
+
+// CreateVolumeSnapshot is used to take a snapshot of a deployment volume.
+func (c ClientCategoryIgniteDeployments) CreateVolumeSnapshot(
+	ctx context.Context, deploymentId, volumeId string, opts ...ClientOption,
+) (*types.VolumeSnapshot, error) {
+	var res types.VolumeSnapshot
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "POST",
+		Path:      volumePath(deploymentId, volumeId) + "/snapshots",
+		ResultKey: "snapshot",
+		Result:    &res,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetVolumeSnapshots is used to get the snapshots of a deployment volume.
+func (c ClientCategoryIgniteDeployments) GetVolumeSnapshots(
+	ctx context.Context, deploymentId, volumeId string, opts ...ClientOption,
+) ([]*types.VolumeSnapshot, error) {
+	var res []*types.VolumeSnapshot
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      volumePath(deploymentId, volumeId) + "/snapshots",
+		ResultKey: "snapshots",
+		Result:    &res,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteVolumeSnapshot is used to delete a snapshot of a deployment volume.
+func (c ClientCategoryIgniteDeployments) DeleteVolumeSnapshot(
+	ctx context.Context, deploymentId, volumeId, snapshotId string, opts ...ClientOption,
+) error {
+	return c.c.do(ctx, ClientArgs{
+		Method: "DELETE",
+		Path:   volumePath(deploymentId, volumeId) + "/snapshots/" + url.PathEscape(snapshotId),
+	}, opts)
+}
+
+// RestoreVolumeSnapshot is used to restore a deployment volume to a snapshot. This replaces all the contents of the
+// volume, so make sure nothing is writing to it.
+func (c ClientCategoryIgniteDeployments) RestoreVolumeSnapshot(
+	ctx context.Context, deploymentId, volumeId, snapshotId string, opts ...ClientOption,
+) error {
+	return c.c.do(ctx, ClientArgs{
+		Method: "POST",
+		Path:   volumePath(deploymentId, volumeId) + "/snapshots/" + url.PathEscape(snapshotId) + "/restore",
+	}, opts)
+}
+
+// GetStorageWarnings is used to get a warning for each kind of deployment storage where the used fraction is at or
+// above the threshold. The threshold is a fraction, so 0.9 will warn when 90% of the storage is used.
+func (c ClientCategoryIgniteDeployments) GetStorageWarnings(
+	ctx context.Context, deploymentId string, threshold float64, opts ...ClientOption,
+) ([]types.StorageUsageWarning, error) {
+	info, err := c.GetStorageStats(ctx, deploymentId, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return info.UsageWarnings(threshold), nil
+}
+
+// GetRollouts is used to get a paginator for the rollout history of a deployment. The newest rollouts are returned first.
+func (c ClientCategoryIgniteDeployments) GetRollouts(deploymentId string) *Paginator[*types.DeploymentRollout] {
+	return &Paginator[*types.DeploymentRollout]{
+		c:         c.c,
+		total:     -1,
+		path:      "/ignite/deployments/" + url.PathEscape(deploymentId) + "/rollouts",
+		resultKey: "rollouts",
+		sortBy:    "created_at",
+		orderBy:   "desc",
+	}
+}
+
+// GetRollout is used to get a rollout of a deployment by its ID.
+func (c ClientCategoryIgniteDeployments) GetRollout(
+	ctx context.Context, deploymentId, rolloutId string, opts ...ClientOption,
+) (*types.DeploymentRollout, error) {
+	var r types.DeploymentRollout
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      "/ignite/deployments/" + url.PathEscape(deploymentId) + "/rollouts/" + url.PathEscape(rolloutId),
+		ResultKey: "rollout",
+		Result:    &r,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Used to create a rollout with the body specified.
+func (c ClientCategoryIgniteDeployments) createRollout(
+	ctx context.Context, deploymentId string, body any, opts []ClientOption,
+) (*types.DeploymentRollout, error) {
+	var r types.DeploymentRollout
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "POST",
+		Path:      "/ignite/deployments/" + url.PathEscape(deploymentId) + "/rollouts",
+		Body:      body,
+		ResultKey: "rollout",
+		Result:    &r,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Rollout is used to trigger a new rollout of a deployment. This will recreate all the containers with the
+// current deployment configuration.
+func (c ClientCategoryIgniteDeployments) Rollout(
+	ctx context.Context, deploymentId string, opts ...ClientOption,
+) (*types.DeploymentRollout, error) {
+	return c.createRollout(ctx, deploymentId, nil, opts)
+}
+
+// AcknowledgeRollout is used to acknowledge a rollout by its ID. This is generally used to dismiss a failed rollout.
+func (c ClientCategoryIgniteDeployments) AcknowledgeRollout(
+	ctx context.Context, deploymentId, rolloutId string, opts ...ClientOption,
+) (*types.DeploymentRollout, error) {
+	var r types.DeploymentRollout
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "PATCH",
+		Path:      "/ignite/deployments/" + url.PathEscape(deploymentId) + "/rollouts/" + url.PathEscape(rolloutId),
+		Body:      map[string]bool{"acknowledged": true},
+		ResultKey: "rollout",
+		Result:    &r,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Rollback is used to roll a deployment back to the build digest that was used by a previous rollout. An error is
+// returned if the rollout specified does not have a build with a digest attached.
+func (c ClientCategoryIgniteDeployments) Rollback(
+	ctx context.Context, deploymentId, rolloutId string, opts ...ClientOption,
+) (*types.DeploymentRollout, error) {
+	r, err := c.GetRollout(ctx, deploymentId, rolloutId, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if r.Build == nil || r.Build.Digest == "" {
+		return nil, errors.New("rollout " + rolloutId + " does not have a build digest to roll back to")
+	}
+	return c.createRollout(ctx, deploymentId, map[string]string{"digest": r.Build.Digest}, opts)
+}
@@ -1,10 +1,13 @@
 package hop
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.hop.io/sdk/types"
 )
 
@@ -15,13 +18,91 @@ func TestClient_Ignite_Gateways_AddDomain(t *testing.T) {
 		wantPath:      "/ignite/gateways/test%20test/domains",
 		wantIgnore404: false,
 		wantBody:      map[string]any{"domain": "example.com"},
+		wantResultKey: "domain",
 		tokenType:     "pat",
 	}
 	testApiSingleton(c,
 		&ClientCategoryIgniteGateways{c: c},
 		"AddDomain",
 		[]any{"test test", "example.com"},
-		nil)
+		&types.Domain{Domain: "example.com", State: types.DomainStatePending})
+}
+
+func TestClient_Ignite_Gateways_GetAllDomains(t *testing.T) {
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "GET",
+		wantPath:       "/ignite/domains",
+		wantResultKey:  "domains",
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		tokenType:      "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteGateways{c: c},
+		"GetAllDomains",
+		[]any{WithProjectID("test123")},
+		[]*types.Domain{{Domain: "example.com"}})
+}
+
+func TestClient_Ignite_Gateways_SetDomainRedirect(t *testing.T) {
+	redirect := &types.DomainRedirect{URL: "https://example.com", StatusCode: 301}
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "PATCH",
+		wantPath:      "/ignite/domains/test%20test",
+		wantBody:      map[string]*types.DomainRedirect{"redirect": redirect},
+		wantResultKey: "domain",
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteGateways{c: c},
+		"SetDomainRedirect",
+		[]any{"test test", redirect},
+		&types.Domain{Domain: "www.example.com", Redirect: redirect})
+}
+
+func TestClient_Ignite_Gateways_SetDomainRedirect_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+
+		redirect *types.DomainRedirect
+		wantErr  string
+	}{
+		{
+			name:     "missing url",
+			redirect: &types.DomainRedirect{StatusCode: 301},
+			wantErr:  "redirect url must be specified",
+		},
+		{
+			name:     "invalid status code",
+			redirect: &types.DomainRedirect{URL: "https://example.com", StatusCode: 200},
+			wantErr:  "redirect status code must be 301, 302, 307 or 308",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := ClientCategoryIgniteGateways{c: &mockClientDoer{t: t}}
+			_, err := g.SetDomainRedirect(context.Background(), "domain_1", tt.redirect)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestClient_Ignite_Gateways_SetHopshDomainEnabled(t *testing.T) {
+	enabled := true
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "PATCH",
+		wantPath:      "/ignite/gateways/test%20test",
+		wantResultKey: "gateway",
+		wantBody:      types.IgniteGatewayUpdateOpts{HopshDomainEnabled: &enabled},
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteGateways{c: c},
+		"SetHopshDomainEnabled",
+		[]any{"test test", true},
+		&types.Gateway{ID: "hello", HopshDomainEnabled: true})
 }
 
 func TestClient_Ignite_Gateways_GetDomain(t *testing.T) {
@@ -103,6 +184,12 @@ func TestClient_Ignite_Gateways_Update(t *testing.T) {
 		&types.Gateway{ID: "hello"})
 }
 
+func TestClient_Ignite_Gateways_Update_Invalid(t *testing.T) {
+	g := ClientCategoryIgniteGateways{c: &mockClientDoer{t: t}}
+	_, err := g.Update(context.Background(), "test", types.IgniteGatewayUpdateOpts{TargetPort: 70000})
+	assert.EqualError(t, err, "target port must be between 1 and 65535")
+}
+
 func TestClient_Ignite_Deployments_Create(t *testing.T) {
 	deploymentConfig := &types.DeploymentConfig{
 		DeploymentConfigPartial: types.DeploymentConfigPartial{
@@ -278,6 +365,16 @@ func TestClient_Ignite_Deployments_CreateGateway(t *testing.T) {
 		&types.Gateway{ID: "hello"})
 }
 
+func TestClient_Ignite_Deployments_CreateGateway_Invalid(t *testing.T) {
+	d := ClientCategoryIgniteDeployments{c: &mockClientDoer{t: t}}
+	_, err := d.CreateGateway(context.Background(), types.GatewayCreationOptions{
+		DeploymentID: "test",
+		Type:         types.GatewayTypeExternal,
+		Protocol:     types.GatewayProtocolUDP,
+	})
+	assert.EqualError(t, err, "target port must be set on udp gateways")
+}
+
 func TestClient_Ignite_Deployments_Scale(t *testing.T) {
 	c := &mockClientDoer{
 		t:              t,
@@ -379,6 +476,160 @@ func TestClient_Ignite_Containers_Start(t *testing.T) {
 		nil)
 }
 
+func TestClient_Ignite_Containers_Get(t *testing.T) {
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "GET",
+		wantPath:       "/ignite/containers/test%20test",
+		wantResultKey:  "container",
+		wantIgnore404:  false,
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		tokenType:      "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteContainers{c: c},
+		"Get",
+		[]any{"test test", WithProjectID("test123")},
+		&types.Container{ID: "hello"})
+}
+
+func TestClient_Ignite_Containers_SetOverrides(t *testing.T) {
+	tests := []struct {
+		name string
+
+		overrides *types.Resources
+		wantBody  any
+	}{
+		{
+			name:      "partial",
+			overrides: &types.Resources{VCPU: 2},
+			wantBody:  map[string]any{"vcpu": 2.0},
+		},
+		{
+			name:      "full",
+			overrides: &types.Resources{VCPU: 2, RAM: "4GB", VGPU: []types.VGPU{}},
+			wantBody:  map[string]any{"vcpu": 2.0, "ram": types.Size("4GB"), "vgpu": []types.VGPU{}},
+		},
+		{name: "clear"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &mockClientDoer{
+				t:              t,
+				wantMethod:     "PATCH",
+				wantPath:       "/ignite/containers/test%20test",
+				wantBody:       map[string]any{"overrides": tt.wantBody},
+				wantResultKey:  "container",
+				wantIgnore404:  false,
+				wantClientOpts: []ClientOption{WithProjectID("test123")},
+				tokenType:      "pat",
+			}
+			testApiSingleton(c,
+				&ClientCategoryIgniteContainers{c: c},
+				"SetOverrides",
+				[]any{"test test", tt.overrides, WithProjectID("test123")},
+				&types.Container{ID: "hello", Overrides: tt.overrides})
+		})
+	}
+}
+
+func TestClient_Ignite_Containers_Restart(t *testing.T) {
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "POST",
+		wantPath:       "/ignite/containers/test%20test/restart",
+		wantIgnore404:  false,
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		tokenType:      "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteContainers{c: c},
+		"Restart",
+		[]any{"test test", WithProjectID("test123")},
+		nil)
+}
+
+func TestClient_Ignite_Containers_Create(t *testing.T) {
+	tests := []struct {
+		name string
+
+		returns []*types.Container
+		wantErr string
+	}{
+		{
+			name:    "success",
+			returns: []*types.Container{{ID: "hello"}},
+		},
+		{
+			name:    "empty response",
+			returns: []*types.Container{},
+			wantErr: "api response error: no containers were returned - please report this to the go-hop github repository",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+				assert.Equal(t, "POST", a.Method)
+				assert.Equal(t, "/ignite/deployments/test%20test/containers", a.Path)
+				assert.Equal(t, "containers", a.ResultKey)
+				assert.Nil(t, a.Body)
+				setResult(a, tt.returns)
+				return nil
+			}}
+			container, err := (&ClientCategoryIgniteContainers{c: c}).Create(context.Background(), "test test")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.returns[0], container)
+		})
+	}
+}
+
+func TestClient_Ignite_Containers_CreateMany(t *testing.T) {
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "POST",
+		wantPath:       "/ignite/deployments/test%20test/containers",
+		wantBody:       map[string]uint{"count": 2},
+		wantResultKey:  "containers",
+		wantIgnore404:  false,
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		tokenType:      "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteContainers{c: c},
+		"CreateMany",
+		[]any{"test test", uint(2), WithProjectID("test123")},
+		[]*types.Container{{ID: "hello"}, {ID: "world"}})
+
+	_, err := (&ClientCategoryIgniteContainers{c: c}).CreateMany(context.Background(), "test test", 0)
+	assert.EqualError(t, err, "container count must be greater than 0")
+}
+
+func TestClient_Ignite_Containers_CreateWithOverrides(t *testing.T) {
+	overrides := []types.Resources{{VCPU: 1}, {RAM: "2GB"}}
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "POST",
+		wantPath:       "/ignite/deployments/test%20test/containers",
+		wantBody:       map[string]any{"count": 2, "overrides": overrides},
+		wantResultKey:  "containers",
+		wantIgnore404:  false,
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		tokenType:      "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteContainers{c: c},
+		"CreateWithOverrides",
+		[]any{"test test", overrides, WithProjectID("test123")},
+		[]*types.Container{{ID: "hello", Overrides: &overrides[0]}, {ID: "world", Overrides: &overrides[1]}})
+
+	_, err := (&ClientCategoryIgniteContainers{c: c}).CreateWithOverrides(context.Background(), "test test", nil)
+	assert.EqualError(t, err, "at least one override must be specified")
+}
+
 func TestClient_Ignite_Deployments_NewHealthCheck(t *testing.T) {
 	tests := []struct {
 		name string
@@ -529,3 +780,275 @@ func TestClient_Ignite_Deployments_GetStorageStats(t *testing.T) {
 			},
 		})
 }
+
+func TestClient_Ignite_Deployments_ResizeVolume(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "POST",
+		wantPath:      "/ignite/deployments/test%20test/volumes/vol%201/resize",
+		wantBody:      map[string]types.Size{"size": types.Gigabytes(10)},
+		wantResultKey: "resize",
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"ResizeVolume",
+		[]any{"test test", "vol 1", types.Gigabytes(10)},
+		&types.VolumeResize{VolumeID: "vol 1", Size: types.Gigabytes(10), State: types.VolumeResizeStatePending})
+}
+
+func TestClient_Ignite_Deployments_ResizeVolume_InvalidSize(t *testing.T) {
+	d := ClientCategoryIgniteDeployments{c: &mockClientDoer{t: t}}
+	_, err := d.ResizeVolume(context.Background(), "test", "vol", "10 potatoes")
+	assert.EqualError(t, err, "invalid volume size: 10 potatoes")
+}
+
+func TestClient_Ignite_Deployments_GetVolumeResize(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "GET",
+		wantPath:      "/ignite/deployments/test%20test/volumes/vol%201/resize",
+		wantResultKey: "resize",
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"GetVolumeResize",
+		[]any{"test test", "vol 1"},
+		&types.VolumeResize{VolumeID: "vol 1", Size: types.Gigabytes(10), State: types.VolumeResizeStateCompleted})
+}
+
+func TestClient_Ignite_Deployments_VolumeResizePending(t *testing.T) {
+	tests := []struct {
+		name string
+
+		resize  *types.VolumeResize
+		err     error
+		want    bool
+		wantErr error
+	}{
+		{name: "pending", resize: &types.VolumeResize{State: types.VolumeResizeStatePending}, want: true},
+		{name: "completed", resize: &types.VolumeResize{State: types.VolumeResizeStateCompleted}},
+		{name: "never resized", err: types.NotFound{Code: "resize_not_found"}},
+		{name: "error", err: types.ServerError("boom"), wantErr: types.ServerError("boom")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+				assert.Equal(t, "/ignite/deployments/test/volumes/vol/resize", a.Path)
+				if tt.err != nil {
+					return tt.err
+				}
+				setResult(a, *tt.resize)
+				return nil
+			}}
+			pending, err := ClientCategoryIgniteDeployments{c: c}.VolumeResizePending(context.Background(), "test", "vol")
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, pending)
+		})
+	}
+}
+
+func TestClient_Ignite_Deployments_CreateVolumeSnapshot(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "POST",
+		wantPath:      "/ignite/deployments/test%20test/volumes/vol%201/snapshots",
+		wantResultKey: "snapshot",
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"CreateVolumeSnapshot",
+		[]any{"test test", "vol 1"},
+		&types.VolumeSnapshot{ID: "snapshot_1", VolumeID: "vol 1", Size: types.Gigabytes(10)})
+}
+
+func TestClient_Ignite_Deployments_GetVolumeSnapshots(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "GET",
+		wantPath:      "/ignite/deployments/test%20test/volumes/vol%201/snapshots",
+		wantResultKey: "snapshots",
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"GetVolumeSnapshots",
+		[]any{"test test", "vol 1"},
+		[]*types.VolumeSnapshot{{ID: "snapshot_1", VolumeID: "vol 1", Size: types.Gigabytes(10)}})
+}
+
+func TestClient_Ignite_Deployments_DeleteVolumeSnapshot(t *testing.T) {
+	c := &mockClientDoer{
+		t:          t,
+		wantMethod: "DELETE",
+		wantPath:   "/ignite/deployments/test%20test/volumes/vol%201/snapshots/snapshot%201",
+		tokenType:  "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"DeleteVolumeSnapshot",
+		[]any{"test test", "vol 1", "snapshot 1"},
+		nil)
+}
+
+func TestClient_Ignite_Deployments_RestoreVolumeSnapshot(t *testing.T) {
+	c := &mockClientDoer{
+		t:          t,
+		wantMethod: "POST",
+		wantPath:   "/ignite/deployments/test%20test/volumes/vol%201/snapshots/snapshot%201/restore",
+		tokenType:  "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"RestoreVolumeSnapshot",
+		[]any{"test test", "vol 1", "snapshot 1"},
+		nil)
+}
+
+func TestClient_Ignite_Deployments_GetStorageWarnings(t *testing.T) {
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		assert.Equal(t, "/ignite/deployments/test/storage", a.Path)
+		setResult(a, types.DeploymentStorageInfo{
+			Volume:     &types.DeploymentStorageSize{ProvisionedSize: 1000, UsedSize: 950},
+			BuildCache: &types.DeploymentStorageSize{ProvisionedSize: 1000, UsedSize: 100},
+		})
+		return nil
+	}}
+	warnings, err := ClientCategoryIgniteDeployments{c: c}.GetStorageWarnings(context.Background(), "test", 0.9)
+	require.NoError(t, err)
+	assert.Equal(t, []types.StorageUsageWarning{{
+		Kind:         types.StorageKindVolume,
+		Used:         types.Megabytes(950),
+		Provisioned:  types.Megabytes(1000),
+		UsedFraction: 0.95,
+	}}, warnings)
+}
+
+func TestClient_Ignite_Deployments_GetRollouts(t *testing.T) {
+	c := &mockClientDoer{}
+	res := (&ClientCategoryIgniteDeployments{c: c}).GetRollouts("test test")
+	assert.Equal(t, res, &Paginator[*types.DeploymentRollout]{
+		c:         c,
+		total:     -1,
+		path:      "/ignite/deployments/test%20test/rollouts",
+		resultKey: "rollouts",
+		sortBy:    "created_at",
+		orderBy:   "desc",
+	})
+}
+
+func TestClient_Ignite_Deployments_GetRollout(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "GET",
+		wantPath:      "/ignite/deployments/test%20test/rollouts/hello%20world",
+		wantResultKey: "rollout",
+		wantIgnore404: false,
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"GetRollout",
+		[]any{"test test", "hello world"},
+		&types.DeploymentRollout{ID: "hello"})
+}
+
+func TestClient_Ignite_Deployments_Rollout(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "POST",
+		wantPath:      "/ignite/deployments/test%20test/rollouts",
+		wantResultKey: "rollout",
+		wantIgnore404: false,
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"Rollout",
+		[]any{"test test"},
+		&types.DeploymentRollout{ID: "hello"})
+}
+
+func TestClient_Ignite_Deployments_AcknowledgeRollout(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "PATCH",
+		wantPath:      "/ignite/deployments/test%20test/rollouts/hello%20world",
+		wantResultKey: "rollout",
+		wantBody:      map[string]bool{"acknowledged": true},
+		wantIgnore404: false,
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryIgniteDeployments{c: c},
+		"AcknowledgeRollout",
+		[]any{"test test", "hello world"},
+		&types.DeploymentRollout{ID: "hello", Acknowledged: true})
+}
+
+func TestClient_Ignite_Deployments_Rollback(t *testing.T) {
+	tests := []struct {
+		name string
+
+		rollout types.DeploymentRollout
+		getErr  error
+
+		wantErr  string
+		wantBody any
+	}{
+		{
+			name:    "get error",
+			getErr:  errors.New("cat tripped on wire"),
+			wantErr: "cat tripped on wire",
+		},
+		{
+			name:    "no build",
+			rollout: types.DeploymentRollout{ID: "rollout_1"},
+			wantErr: "rollout rollout_1 does not have a build digest to roll back to",
+		},
+		{
+			name:    "no digest",
+			rollout: types.DeploymentRollout{ID: "rollout_1", Build: &types.Build{ID: "build_1"}},
+			wantErr: "rollout rollout_1 does not have a build digest to roll back to",
+		},
+		{
+			name:     "success",
+			rollout:  types.DeploymentRollout{ID: "rollout_1", Build: &types.Build{Digest: "sha256:abc"}},
+			wantBody: map[string]string{"digest": "sha256:abc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, opts []ClientOption) error {
+				calls++
+				if calls == 1 {
+					assert.Equal(t, "GET", a.Method)
+					assert.Equal(t, "/ignite/deployments/test/rollouts/rollout_1", a.Path)
+					if tt.getErr != nil {
+						return tt.getErr
+					}
+					setResult(a, tt.rollout)
+					return nil
+				}
+				assert.Equal(t, "POST", a.Method)
+				assert.Equal(t, "/ignite/deployments/test/rollouts", a.Path)
+				assert.Equal(t, tt.wantBody, a.Body)
+				setResult(a, types.DeploymentRollout{ID: "rollout_2"})
+				return nil
+			}}
+			r, err := (&ClientCategoryIgniteDeployments{c: c}).Rollback(context.Background(), "test", "rollout_1")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, r)
+				assert.Equal(t, 1, calls)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &types.DeploymentRollout{ID: "rollout_2"}, r)
+			assert.Equal(t, 2, calls)
+		})
+	}
+}
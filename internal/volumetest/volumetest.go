@@ -0,0 +1,305 @@
+// Package volumetest provides an in-memory stand-in for the Ignite volume files API. This is only meant to be used
+// by tests.
+package volumetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix is the path of the files API for the deployment "test" and the volume "vol".
+const Prefix = "/ignite/deployments/test/volumes/vol/files"
+
+// File is used to define a file or directory in the volume.
+type File struct {
+	Dir       bool
+	Data      []byte
+	Perm      int
+	UpdatedAt time.Time
+}
+
+type item struct {
+	Name        string `json:"name"`
+	Directory   bool   `json:"directory"`
+	Permissions int    `json:"permissions"`
+	UpdatedAt   string `json:"updated_at"`
+	Size        int64  `json:"size"`
+}
+
+// Server is used to define the stand-in for the files API. The root directory is stored as ".".
+type Server struct {
+	// Now is the time used for every change.
+	Now time.Time
+
+	// NoRanges is used to make the server ignore range requests.
+	NoRanges bool
+
+	// NoWrites is used to make the server behave as if the write endpoints do not exist.
+	NoWrites bool
+
+	mu    sync.Mutex
+	files map[string]*File
+}
+
+// NewServer is used to make a server with an empty root directory.
+func NewServer() *Server {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &Server{
+		Now:   now,
+		files: map[string]*File{".": {Dir: true, Perm: 0o755, UpdatedAt: now}},
+	}
+}
+
+// AddFile is used to add a file to the volume. Parent directories are created automatically.
+func (s *Server) AddFile(name, content string, perm int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if _, ok := s.files[dir]; !ok {
+			s.files[dir] = &File{Dir: true, Perm: 0o755, UpdatedAt: s.Now}
+		}
+	}
+	s.files[name] = &File{Data: []byte(content), Perm: perm, UpdatedAt: s.Now}
+}
+
+// ReadFile is used to get the contents of a file. Returns false if it is not a file.
+func (s *Server) ReadFile(name string) (string, bool) {
+	f, ok := s.Stat(name)
+	if !ok || f.Dir {
+		return "", false
+	}
+	return string(f.Data), true
+}
+
+// Stat is used to get a copy of the file or directory specified.
+func (s *Server) Stat(name string) (File, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.files[name]
+	if !ok {
+		return File{}, false
+	}
+	return *f, true
+}
+
+// SetPerm is used to change the permissions of a file or directory which exists.
+func (s *Server) SetPerm(name string, perm int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[name].Perm = perm
+}
+
+// Paths is used to get all the sorted paths in the volume.
+func (s *Server) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := make([]string, 0, len(s.files))
+	for k := range s.files {
+		a = append(a, k)
+	}
+	sort.Strings(a)
+	return a
+}
+
+func (s *Server) item(name string, f *File) item {
+	return item{
+		Name:        path.Base(name),
+		Directory:   f.Dir,
+		Permissions: f.Perm,
+		UpdatedAt:   f.UpdatedAt.Format(time.RFC3339),
+		Size:        int64(len(f.Data)),
+	}
+}
+
+// Returns the sorted children of the directory specified.
+func (s *Server) children(dir string) []string {
+	var names []string
+	for k := range s.files {
+		if k != "." && path.Dir(k) == dir {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"success": false,
+		"error":   map[string]string{"code": code, "message": code},
+	})
+}
+
+func writeData(w http.ResponseWriter, data any) {
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "data": data})
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.EscapedPath()
+	if !strings.HasPrefix(p, Prefix) {
+		writeError(w, http.StatusNotFound, "not_found")
+		return
+	}
+	name, err := url.PathUnescape(strings.TrimPrefix(strings.TrimPrefix(p, Prefix), "/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_path")
+		return
+	}
+	if name == "" {
+		name = "."
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Method == "GET" {
+		s.get(w, r, name)
+		return
+	}
+	if s.NoWrites {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	switch r.Method {
+	case "PUT":
+		s.put(w, r, name)
+	case "POST":
+		s.mkdir(w, r, name)
+	case "DELETE":
+		s.delete(w, r, name)
+	case "PATCH":
+		s.patch(w, r, name)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// Returns if the parent of the path specified is a directory.
+func (s *Server) parentOk(name string) bool {
+	parent, ok := s.files[path.Dir(name)]
+	return ok && parent.Dir
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, name string) {
+	f, ok := s.files[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "file_not_found")
+		return
+	}
+	if r.URL.Query().Get("stream") == "true" {
+		if s.NoRanges {
+			_, _ = w.Write(f.Data)
+			return
+		}
+		http.ServeContent(w, r, name, f.UpdatedAt, bytes.NewReader(f.Data))
+		return
+	}
+	if !f.Dir {
+		writeData(w, map[string]any{"folder": false, "file": s.item(name, f)})
+		return
+	}
+	items := []item{}
+	for _, child := range s.children(name) {
+		items = append(items, s.item(child, s.files[child]))
+	}
+	writeData(w, map[string]any{"folder": true, "file": items})
+}
+
+func (s *Server) put(w http.ResponseWriter, r *http.Request, name string) {
+	if !s.parentOk(name) {
+		writeError(w, http.StatusNotFound, "file_not_found")
+		return
+	}
+	if f, ok := s.files[name]; ok && f.Dir {
+		writeError(w, http.StatusBadRequest, "file_exists")
+		return
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body")
+		return
+	}
+	perm, _ := strconv.Atoi(r.URL.Query().Get("permissions"))
+	s.files[name] = &File{Data: b, Perm: perm, UpdatedAt: s.Now}
+	writeData(w, map[string]any{})
+}
+
+func (s *Server) mkdir(w http.ResponseWriter, r *http.Request, name string) {
+	var body struct {
+		Permissions int `json:"permissions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body")
+		return
+	}
+	if _, ok := s.files[name]; ok {
+		writeError(w, http.StatusBadRequest, "file_exists")
+		return
+	}
+	if !s.parentOk(name) {
+		writeError(w, http.StatusNotFound, "file_not_found")
+		return
+	}
+	s.files[name] = &File{Dir: true, Perm: body.Permissions, UpdatedAt: s.Now}
+	writeData(w, map[string]any{})
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, name string) {
+	f, ok := s.files[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "file_not_found")
+		return
+	}
+	if f.Dir && len(s.children(name)) != 0 && r.URL.Query().Get("recursive") != "true" {
+		writeError(w, http.StatusBadRequest, "directory_not_empty")
+		return
+	}
+	for k := range s.files {
+		if k == name || strings.HasPrefix(k, name+"/") {
+			delete(s.files, k)
+		}
+	}
+	writeData(w, map[string]any{})
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, name string) {
+	f, ok := s.files[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "file_not_found")
+		return
+	}
+	var body struct {
+		Path        *string `json:"path"`
+		Permissions *int    `json:"permissions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body")
+		return
+	}
+	if body.Permissions != nil {
+		f.Perm = *body.Permissions
+	}
+	if body.Path != nil {
+		if !s.parentOk(*body.Path) {
+			writeError(w, http.StatusNotFound, "file_not_found")
+			return
+		}
+		for k, v := range s.files {
+			if k == name || strings.HasPrefix(k, name+"/") {
+				delete(s.files, k)
+				s.files[*body.Path+strings.TrimPrefix(k, name)] = v
+			}
+		}
+	}
+	writeData(w, map[string]any{})
+}
@@ -2,6 +2,7 @@ package leap
 
 import (
 	"compress/zlib"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -81,10 +82,14 @@ type Client struct {
 	channelWaiter eventWaiter[*types.ChannelPartial]
 
 	channelQueue     []*queueDispatcher[types.LeapChannelEvent]
+	channelListeners []*channelListener
 	channelQueueLock sync.RWMutex
 
 	messageQueue     []*queueDispatcher[types.LeapMessageEvent]
 	messageQueueLock sync.RWMutex
+
+	replays    map[string]*replayBuffer
+	replayLock sync.Mutex
 }
 
 // MessageEventChannel is used to get a channel that will receive all message events.
@@ -106,6 +111,31 @@ func (c *Client) ChannelEventChannel() <-chan types.LeapChannelEvent {
 	return ch
 }
 
+// Defines a function which is called with every channel event. This is a pointer so it can be found to be removed.
+type channelListener struct {
+	fn func(types.LeapChannelEvent)
+}
+
+// AddChannelEventListener is used to add a function which is called with every channel event. The function is called
+// from the read loop, so it must not block. Unlike ChannelEventChannel, the listener can be removed by calling the
+// function which is returned.
+func (c *Client) AddChannelEventListener(fn func(types.LeapChannelEvent)) (remove func()) {
+	l := &channelListener{fn: fn}
+	c.channelQueueLock.Lock()
+	c.channelListeners = append(c.channelListeners, l)
+	c.channelQueueLock.Unlock()
+	return func() {
+		c.channelQueueLock.Lock()
+		defer c.channelQueueLock.Unlock()
+		for i, v := range c.channelListeners {
+			if v == l {
+				c.channelListeners = append(c.channelListeners[:i], c.channelListeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
 // Closes all queues in the client.
 func (c *Client) closeAllQueues() {
 	c.channelQueueLock.Lock()
@@ -290,6 +320,27 @@ func (c *Client) InitEvent() *types.LeapInitEvent {
 	return c.initEvent.get()
 }
 
+// Sends the channel event to all the channel event queues and listeners.
+func (c *Client) dispatchChannel(e types.LeapChannelEvent) {
+	c.channelQueueLock.RLock()
+	for _, v := range c.channelQueue {
+		v.dispatch(e)
+	}
+	for _, v := range c.channelListeners {
+		v.fn(e)
+	}
+	c.channelQueueLock.RUnlock()
+}
+
+// Unmarshals the dispatch event into T and sends it to all the channel event queues.
+func dispatchChannelEvent[T any](c *Client, x dispatchEvent) {
+	var e T
+	if err := unmarshalPacket(x, &e); err != nil {
+		return
+	}
+	c.dispatchChannel(e)
+}
+
 // Used to handle dispatching events.
 func (c *Client) dispatchEvent(r json.RawMessage) { //nolint:gocognit
 	var x dispatchEvent
@@ -320,11 +371,7 @@ func (c *Client) dispatchEvent(r json.RawMessage) { //nolint:gocognit
 			return
 		}
 		if ok := c.channelWaiter.signal(e.Channel.ID, e.Channel, nil); !ok {
-			c.channelQueueLock.RLock()
-			for _, v := range c.channelQueue {
-				v.dispatch(e)
-			}
-			c.channelQueueLock.RUnlock()
+			c.dispatchChannel(e)
 		}
 	case "UNAVAILABLE":
 		var e types.LeapUnavailableEvent
@@ -332,52 +379,30 @@ func (c *Client) dispatchEvent(r json.RawMessage) { //nolint:gocognit
 			return
 		}
 		if ok := c.channelWaiter.signal(e.ChannelID, nil, e); !ok {
-			c.channelQueueLock.RLock()
-			for _, v := range c.channelQueue {
-				v.dispatch(e)
-			}
-			c.channelQueueLock.RUnlock()
+			c.dispatchChannel(e)
 		}
 	case "MESSAGE", "DIRECT_MESSAGE": // MESSAGE and DIRECT_MESSAGE are the same inside packet.
 		var e types.LeapMessageEvent
 		if err = unmarshalPacket(x, &e); err != nil {
 			return
 		}
-		c.messageQueueLock.RLock()
-		for _, v := range c.messageQueue {
-			v.dispatch(e)
+		if !c.bufferReplay(e) {
+			c.dispatchMessage(e)
 		}
-		c.messageQueueLock.RUnlock()
 	case "STATE_UPDATE":
-		var e types.LeapChannelStateUpdateEvent
-		if err = unmarshalPacket(x, &e); err != nil {
-			return
-		}
-		c.channelQueueLock.RLock()
-		for _, v := range c.channelQueue {
-			v.dispatch(e)
-		}
-		c.channelQueueLock.RUnlock()
+		dispatchChannelEvent[types.LeapChannelStateUpdateEvent](c, x)
 	case "PIPE_ROOM_AVAILABLE":
-		var e types.LeapPipeRoomAvailableEvent
-		if err = unmarshalPacket(x, &e); err != nil {
-			return
-		}
-		c.channelQueueLock.RLock()
-		for _, v := range c.channelQueue {
-			v.dispatch(e)
-		}
-		c.channelQueueLock.RUnlock()
+		dispatchChannelEvent[types.LeapPipeRoomAvailableEvent](c, x)
 	case "PIPE_ROOM_UPDATE":
-		var e types.LeapPipeRoomUpdateEvent
-		if err = unmarshalPacket(x, &e); err != nil {
-			return
-		}
-		c.channelQueueLock.RLock()
-		for _, v := range c.channelQueue {
-			v.dispatch(e)
-		}
-		c.channelQueueLock.RUnlock()
+		dispatchChannelEvent[types.LeapPipeRoomUpdateEvent](c, x)
+	case "CONTAINER_UPDATE":
+		dispatchChannelEvent[types.LeapContainerUpdateEvent](c, x)
+	case "ROLLOUT_UPDATE":
+		dispatchChannelEvent[types.LeapRolloutUpdateEvent](c, x)
+	case "BUILD_UPDATE":
+		dispatchChannelEvent[types.LeapBuildUpdateEvent](c, x)
+	case "HEALTH_CHECK_UPDATE":
+		dispatchChannelEvent[types.LeapHealthCheckUpdateEvent](c, x)
 	default:
 		c.logger.Warn("unknown dispatch event", map[string]any{
 			"event_code": x.DispatchEventCode,
@@ -385,14 +410,25 @@ func (c *Client) dispatchEvent(r json.RawMessage) { //nolint:gocognit
 	}
 }
 
-// Subscribe subscribes to a channel.
-func (c *Client) Subscribe(channelId string) (*types.ChannelPartial, error) {
+// Subscribe subscribes to a channel. If WithReplay is used, any messages published since the time or message ID given
+// are sent to the message channels before any live messages. Use WithReplayContext to cancel or time out fetching the
+// history. If the history cannot be fetched, the channel is still subscribed to and returned alongside the error.
+func (c *Client) Subscribe(channelId string, opts ...SubscribeOption) (*types.ChannelPartial, error) {
+	var o subscribeOptions
+	for _, v := range opts {
+		v(&o)
+	}
+
 	c.wsLock.RLock()
 	ws := c.ws
 	c.wsLock.RUnlock()
 	if ws == nil {
 		return nil, net.ErrClosed
 	}
+	if o.history != nil {
+		// Buffer the live messages until the history has been dispatched.
+		c.startReplay(channelId)
+	}
 	err := c.writePayload(ws, &payload{
 		Op: 0,
 		Data: rawify(dispatchEvent{
@@ -403,10 +439,26 @@ func (c *Client) Subscribe(channelId string) (*types.ChannelPartial, error) {
 			DispatchEventCode: "SUBSCRIBE",
 		}),
 	})
+	var ch *types.ChannelPartial
+	if err == nil {
+		ch, err = c.channelWaiter.wait(channelId)
+	}
+	if o.history == nil {
+		return ch, err
+	}
 	if err != nil {
+		// Stop buffering and dispatch anything that was received.
+		c.finishReplay(channelId, nil)
 		return nil, err
 	}
-	return c.channelWaiter.wait(channelId)
+
+	ctx := o.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	history, err := o.history(ctx, channelId, o.since, o.afterId)
+	c.finishReplay(channelId, history)
+	return ch, err
 }
 
 // Defines the read loop.
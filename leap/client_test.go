@@ -0,0 +1,105 @@
+package leap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.hop.io/sdk/types"
+)
+
+func TestClient_dispatchEvent_Channel(t *testing.T) {
+	details := types.LeapDispatchEventDetails{ChannelID: "project_1"}
+	tests := []struct {
+		name string
+
+		raw  string
+		want types.LeapChannelEvent
+	}{
+		{
+			name: "state update",
+			raw:  `{"e":"STATE_UPDATE","c":"project_1","d":{"state":{"a":"b"}}}`,
+			want: types.LeapChannelStateUpdateEvent{LeapDispatchEventDetails: details, State: map[string]any{"a": "b"}},
+		},
+		{
+			name: "pipe room update",
+			raw:  `{"e":"PIPE_ROOM_UPDATE","c":"project_1","d":{"pipe_room":{"id":"room_1","state":"live"},"connection":{}}}`,
+			want: types.LeapPipeRoomUpdateEvent{
+				LeapDispatchEventDetails: details,
+				PipeRoom:                 types.Room{ID: "room_1", State: types.RoomStateLive},
+			},
+		},
+		{
+			name: "container update",
+			raw:  `{"e":"CONTAINER_UPDATE","c":"project_1","d":{"container":{"id":"container_1","state":"running"}}}`,
+			want: types.LeapContainerUpdateEvent{
+				LeapDispatchEventDetails: details,
+				Container:                &types.Container{ID: "container_1", State: types.ContainerStateRunning},
+			},
+		},
+		{
+			name: "rollout update",
+			raw:  `{"e":"ROLLOUT_UPDATE","c":"project_1","d":{"rollout":{"id":"rollout_1","count":2}}}`,
+			want: types.LeapRolloutUpdateEvent{
+				LeapDispatchEventDetails: details,
+				Rollout:                  &types.DeploymentRollout{ID: "rollout_1", Count: 2},
+			},
+		},
+		{
+			name: "build update",
+			raw:  `{"e":"BUILD_UPDATE","c":"project_1","d":{"build":{"id":"build_1","deployment_id":"deployment_1"}}}`,
+			want: types.LeapBuildUpdateEvent{
+				LeapDispatchEventDetails: details,
+				Build:                    &types.Build{ID: "build_1", DeploymentID: "deployment_1"},
+			},
+		},
+		{
+			name: "health check update",
+			raw:  `{"e":"HEALTH_CHECK_UPDATE","c":"project_1","d":{"state":{"health_check_id":"hc_1","state":"succeeded"}}}`,
+			want: types.LeapHealthCheckUpdateEvent{
+				LeapDispatchEventDetails: details,
+				State:                    &types.HealthCheckState{HealthCheckID: "hc_1", State: types.HealthCheckStatusSucceeded},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient("", "", nil)
+			ch := c.ChannelEventChannel()
+			c.dispatchEvent([]byte(tt.raw))
+			select {
+			case e := <-ch:
+				assert.Equal(t, tt.want, e)
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for the event")
+			}
+		})
+	}
+}
+
+func TestClient_dispatchEvent_Ignored(t *testing.T) {
+	c := NewClient("", "", nil)
+	ch := c.ChannelEventChannel()
+	c.dispatchEvent([]byte(`{"e":"UNKNOWN_EVENT","c":"project_1","d":{}}`))
+	c.dispatchEvent([]byte(`{"e":"CONTAINER_UPDATE","c":"project_1","d":{"container":"not an object"}}`))
+	c.dispatchEvent([]byte(`not json`))
+	select {
+	case e := <-ch:
+		t.Fatalf("unexpected event: %#v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestClient_AddChannelEventListener(t *testing.T) {
+	c := NewClient("", "", nil)
+	var first, second int
+	removeFirst := c.AddChannelEventListener(func(types.LeapChannelEvent) { first++ })
+	c.AddChannelEventListener(func(types.LeapChannelEvent) { second++ })
+
+	c.dispatchChannel(types.LeapChannelStateUpdateEvent{})
+	removeFirst()
+	removeFirst()
+	c.dispatchChannel(types.LeapChannelStateUpdateEvent{})
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+}
@@ -0,0 +1,113 @@
+package leap
+
+import (
+	"context"
+	"time"
+
+	"go.hop.io/sdk/types"
+)
+
+// MessageHistory is used to get the messages published to a channel since a time or message ID, oldest first. The
+// MessageHistory function on the Channels category of a hop.Client returns one of these.
+type MessageHistory func(ctx context.Context, channelId string, since time.Time, afterId string) ([]*types.ChannelMessage, error)
+
+// SubscribeOption is used to define an option for Subscribe.
+type SubscribeOption func(*subscribeOptions)
+
+type subscribeOptions struct {
+	ctx     context.Context
+	history MessageHistory
+	since   time.Time
+	afterId string
+}
+
+// WithReplay is used to replay the messages which were published to the channel since the time or message ID specified
+// before switching to live delivery. This allows a client that was offline to catch up on what it missed. Messages
+// which are both replayed and delivered live are only sent to the message channels once.
+func WithReplay(since time.Time, afterId string, history MessageHistory) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.history = history
+		o.since = since
+		o.afterId = afterId
+	}
+}
+
+// WithReplayContext is used to set the context used to fetch the history when WithReplay is used. This allows a slow
+// history request to be cancelled or given a deadline. By default, the history request is not cancelled. Live messages
+// for the channel are buffered until the history request returns.
+func WithReplayContext(ctx context.Context) SubscribeOption {
+	return func(o *subscribeOptions) {
+		o.ctx = ctx
+	}
+}
+
+// Defines live messages which were received whilst the history of a channel was being fetched.
+type replayBuffer struct {
+	events []types.LeapMessageEvent
+}
+
+// Starts buffering the live messages of the channel specified.
+func (c *Client) startReplay(channelId string) {
+	c.replayLock.Lock()
+	if c.replays == nil {
+		c.replays = map[string]*replayBuffer{}
+	}
+	c.replays[channelId] = &replayBuffer{}
+	c.replayLock.Unlock()
+}
+
+// Buffers the message if the history of its channel is being fetched. Returns false if the message should be
+// dispatched as normal.
+func (c *Client) bufferReplay(e types.LeapMessageEvent) bool {
+	if e.IsDirectMessage() {
+		return false
+	}
+	c.replayLock.Lock()
+	defer c.replayLock.Unlock()
+	b, ok := c.replays[e.ChannelID]
+	if !ok {
+		return false
+	}
+	b.events = append(b.events, e)
+	return true
+}
+
+// Dispatches a message event to all the message queues.
+func (c *Client) dispatchMessage(e types.LeapMessageEvent) {
+	c.messageQueueLock.RLock()
+	for _, v := range c.messageQueue {
+		v.dispatch(e)
+	}
+	c.messageQueueLock.RUnlock()
+}
+
+// Dispatches the history followed by the buffered live messages which were not in the history, and then switches the
+// channel back to live delivery. This is done with the replay lock held so that no live messages are dispatched in
+// between.
+func (c *Client) finishReplay(channelId string, history []*types.ChannelMessage) {
+	c.replayLock.Lock()
+	defer c.replayLock.Unlock()
+
+	seen := make(map[string]struct{}, len(history))
+	for _, v := range history {
+		if v.ID != "" {
+			seen[v.ID] = struct{}{}
+		}
+		c.dispatchMessage(types.LeapMessageEvent{
+			LeapDispatchEventDetails: types.LeapDispatchEventDetails{ChannelID: channelId},
+			ID:                       v.ID,
+			Data:                     v.Data,
+			EventName:                v.EventName,
+		})
+	}
+
+	if b, ok := c.replays[channelId]; ok {
+		for _, e := range b.events {
+			if _, dupe := seen[e.ID]; dupe && e.ID != "" {
+				continue
+			}
+			c.dispatchMessage(e)
+		}
+		delete(c.replays, channelId)
+	}
+}
@@ -0,0 +1,159 @@
+package leap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+// Defines a stand-in for the websocket which calls a function with each payload written to it.
+type fakeWebSocket struct {
+	onWrite func(p payload)
+}
+
+func (fakeWebSocket) NextReader() (int, io.Reader, error) { return 0, nil, io.EOF }
+
+func (fakeWebSocket) SetReadDeadline(time.Time) error { return nil }
+
+func (fakeWebSocket) Close() error { return nil }
+
+func (w fakeWebSocket) NextWriter(int) (io.WriteCloser, error) {
+	return &fakeWriter{onWrite: w.onWrite}, nil
+}
+
+type fakeWriter struct {
+	bytes.Buffer
+
+	onWrite func(p payload)
+}
+
+func (w *fakeWriter) Close() error {
+	var p payload
+	if err := json.Unmarshal(w.Bytes(), &p); err != nil {
+		return err
+	}
+	if w.onWrite != nil {
+		w.onWrite(p)
+	}
+	return nil
+}
+
+// Returns the raw dispatch event for a message published to the channel specified.
+func messageEvent(channelId, id string) json.RawMessage {
+	return rawify(map[string]any{
+		"e": "MESSAGE",
+		"c": channelId,
+		"d": map[string]any{"e": "event", "d": map[string]any{}, "id": id},
+	})
+}
+
+// Reads the IDs of the next n messages from the channel.
+func readMessageIDs(t *testing.T, ch <-chan types.LeapMessageEvent, n int) []string {
+	t.Helper()
+	ids := make([]string, n)
+	for i := range ids {
+		select {
+		case e := <-ch:
+			ids[i] = e.ChannelID + "/" + e.ID
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for message %d", i)
+		}
+	}
+	return ids
+}
+
+func TestClient_replay(t *testing.T) {
+	c := NewClient("", "", nil)
+	ch := c.MessageEventChannel()
+
+	c.startReplay("c1")
+	c.dispatchEvent(messageEvent("c1", "m2"))
+	c.dispatchEvent(messageEvent("c2", "x1"))
+	c.dispatchEvent(messageEvent("c1", "m3"))
+	c.dispatchEvent(messageEvent("c1", ""))
+
+	// Other channels are not held back by the replay.
+	assert.Equal(t, []string{"c2/x1"}, readMessageIDs(t, ch, 1))
+
+	// The history comes first, and the live message which is also in the history is only sent once.
+	c.finishReplay("c1", []*types.ChannelMessage{{ID: "m1"}, {ID: "m2"}})
+	assert.Equal(t, []string{"c1/m1", "c1/m2", "c1/m3", "c1/"}, readMessageIDs(t, ch, 4))
+
+	// The channel is live again once the replay has finished.
+	c.dispatchEvent(messageEvent("c1", "m2"))
+	assert.Equal(t, []string{"c1/m2"}, readMessageIDs(t, ch, 1))
+}
+
+// Makes a client with a fake websocket which makes every subscribe succeed.
+func newSubscribeTestClient(t *testing.T) *Client {
+	t.Helper()
+	c := NewClient("", "", nil)
+	c.ws = fakeWebSocket{onWrite: func(p payload) {
+		var x dispatchEvent
+		require.NoError(t, json.Unmarshal(p.Data, &x))
+		go func() {
+			// Wait for Subscribe to start waiting for the channel.
+			for !c.channelWaiter.signal(x.ChannelID, &types.ChannelPartial{ID: x.ChannelID}, nil) {
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}}
+	return c
+}
+
+func TestClient_Subscribe_Replay(t *testing.T) {
+	c := newSubscribeTestClient(t)
+	msgs := c.MessageEventChannel()
+	since := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := func(_ context.Context, channelId string, gotSince time.Time, afterId string) ([]*types.ChannelMessage, error) {
+		assert.Equal(t, "c1", channelId)
+		assert.Equal(t, since, gotSince)
+		assert.Equal(t, "m0", afterId)
+
+		// A message published whilst the history is being fetched is in both.
+		c.dispatchEvent(messageEvent("c1", "m2"))
+		c.dispatchEvent(messageEvent("c1", "m3"))
+		return []*types.ChannelMessage{{ID: "m1"}, {ID: "m2"}}, nil
+	}
+	ch, err := c.Subscribe("c1", WithReplay(since, "m0", history))
+	require.NoError(t, err)
+	assert.Equal(t, &types.ChannelPartial{ID: "c1"}, ch)
+	assert.Equal(t, []string{"c1/m1", "c1/m2", "c1/m3"}, readMessageIDs(t, msgs, 3))
+}
+
+func TestClient_Subscribe_ReplayError(t *testing.T) {
+	c := newSubscribeTestClient(t)
+	msgs := c.MessageEventChannel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	history := func(ctx context.Context, _ string, _ time.Time, _ string) ([]*types.ChannelMessage, error) {
+		c.dispatchEvent(messageEvent("c1", "m1"))
+		return nil, ctx.Err()
+	}
+	ch, err := c.Subscribe("c1", WithReplay(time.Time{}, "", history), WithReplayContext(ctx))
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, &types.ChannelPartial{ID: "c1"}, ch)
+
+	// The live messages which were buffered are still sent, and the channel is live again.
+	assert.Equal(t, []string{"c1/m1"}, readMessageIDs(t, msgs, 1))
+	c.dispatchEvent(messageEvent("c1", "m2"))
+	assert.Equal(t, []string{"c1/m2"}, readMessageIDs(t, msgs, 1))
+}
+
+func TestClient_Subscribe_Closed(t *testing.T) {
+	c := NewClient("", "", nil)
+	history := func(context.Context, string, time.Time, string) ([]*types.ChannelMessage, error) {
+		return nil, errors.New("should not be called")
+	}
+	_, err := c.Subscribe("c1", WithReplay(time.Time{}, "", history))
+	assert.Equal(t, net.ErrClosed, err)
+}
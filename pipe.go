@@ -3,6 +3,8 @@ package hop
 import (
 	"context"
 	"net/url"
+	"sync/atomic"
+	"time"
 
 	"go.hop.io/sdk/types"
 )
@@ -25,10 +27,13 @@ func (c ClientCategoryPipeRooms) GetAll(ctx context.Context, opts ...ClientOptio
 	return rooms, nil
 }
 
-// Create is used to create a room.
+// Create is used to create a room. The options are validated before the request is sent.
 func (c ClientCategoryPipeRooms) Create(
 	ctx context.Context, opts types.RoomCreationOptions, clientOpts ...ClientOption,
 ) (*types.Room, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 	var room types.Room
 	err := c.c.do(ctx, ClientArgs{
 		Method:    "POST",
@@ -50,3 +55,201 @@ func (c ClientCategoryPipeRooms) Delete(ctx context.Context, id string, opts ...
 		Path:   "/pipe/rooms/" + url.PathEscape(id),
 	}, opts)
 }
+
+// Get is used to get a room by its ID.
+func (c ClientCategoryPipeRooms) Get(ctx context.Context, id string, opts ...ClientOption) (*types.Room, error) {
+	var room types.Room
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "GET",
+		Path:      "/pipe/rooms/" + url.PathEscape(id),
+		ResultKey: "room",
+		Result:    &room,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// Update is used to update a room. Any blank options are not changed. The options are validated before the request is
+// sent.
+func (c ClientCategoryPipeRooms) Update(
+	ctx context.Context, id string, opts types.RoomUpdateOptions, clientOpts ...ClientOption,
+) (*types.Room, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	var room types.Room
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "PATCH",
+		Path:      "/pipe/rooms/" + url.PathEscape(id),
+		ResultKey: "room",
+		Result:    &room,
+		Body:      opts,
+	}, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// Does a POST request to the room path specified and returns the updated room.
+func (c ClientCategoryPipeRooms) regenerate(ctx context.Context, id, suffix string, opts []ClientOption) (*types.Room, error) {
+	var room types.Room
+	err := c.c.do(ctx, ClientArgs{
+		Method:    "POST",
+		Path:      "/pipe/rooms/" + url.PathEscape(id) + suffix,
+		ResultKey: "room",
+		Result:    &room,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// RegenerateStreamKey is used to replace the stream key of a room, for example if it was leaked. Any streams using
+// the old key will be disconnected. Returns the room with the new stream key.
+func (c ClientCategoryPipeRooms) RegenerateStreamKey(ctx context.Context, id string, opts ...ClientOption) (*types.Room, error) {
+	return c.regenerate(ctx, id, "/stream-key", opts)
+}
+
+// RegenerateJoinToken is used to replace the join token of a room. Clients will need the new token to join the room.
+// Returns the room with the new join token.
+func (c ClientCategoryPipeRooms) RegenerateJoinToken(ctx context.Context, id string, opts ...ClientOption) (*types.Room, error) {
+	return c.regenerate(ctx, id, "/join-token", opts)
+}
+
+// How often room states are polled when waiting without a connected Leap client.
+var roomPollInterval = 5 * time.Second
+
+// How often room states are polled when waiting with a connected Leap client. This is a safety net in case an event is
+// lost, for example whilst Leap is reconnecting.
+var roomLeapPollInterval = time.Minute
+
+// Returns the Leap client from the client options, or nil if there is not one.
+func getLeapClient(opts []ClientOption) leapEventSource {
+	var l leapEventSource
+	for _, v := range opts {
+		if x, ok := v.(leapClientOption); ok {
+			l = x.c
+		}
+	}
+	return l
+}
+
+// Builds the connection map for a room from the playback URLs. This is used when the room state is polled, since the
+// API does not return the connection information. The serving POP is left blank since it is not known without Leap.
+func connectionMapFromRoom(r *types.Room) *types.PipeConnectionMap {
+	m := &types.PipeConnectionMap{}
+	for _, protocol := range r.DeliveryProtocols {
+		u, err := r.PlaybackURL(protocol)
+		if err != nil {
+			continue
+		}
+		conn := &types.PipeConnection{EdgeEndpoint: u, Type: protocol}
+		switch protocol {
+		case types.DeliveryProtocolHLS:
+			m.LLHLS = conn
+		case types.DeliveryProtocolWebRTC:
+			m.WebRTC = conn
+		}
+	}
+	return m
+}
+
+// Returns the room and connection map from a Leap event if it is an update for the room specified.
+func roomFromEvent(e types.LeapChannelEvent, roomId string) (*types.Room, *types.PipeConnectionMap, bool) {
+	var x types.LeapPipeRoomAvailableEvent
+	switch v := e.(type) {
+	case types.LeapPipeRoomAvailableEvent:
+		x = v
+	case types.LeapPipeRoomUpdateEvent:
+		x = types.LeapPipeRoomAvailableEvent(v)
+	default:
+		return nil, nil, false
+	}
+	if x.PipeRoom.ID != roomId {
+		return nil, nil, false
+	}
+	return &x.PipeRoom, &x.Connection, true
+}
+
+// Waits for the room to be in the state specified. Leap events are used if a connected Leap client is in the options,
+// otherwise the room is polled.
+func (c ClientCategoryPipeRooms) waitState(
+	ctx context.Context, roomId string, state types.RoomState, opts []ClientOption,
+) (*types.PipeConnectionMap, error) {
+	events := make(chan types.LeapChannelEvent, 8)
+	var dropped uint32
+	l := getLeapClient(opts)
+	if l != nil {
+		// Add the listener before checking the state so that no updates are missed.
+		remove := l.AddChannelEventListener(func(e types.LeapChannelEvent) {
+			if _, _, ok := roomFromEvent(e, roomId); ok {
+				select {
+				case events <- e:
+				default:
+					// The room is being updated faster than it can be checked, so drop the event and poll on the
+					// next tick instead.
+					atomic.StoreUint32(&dropped, 1)
+				}
+			}
+		})
+		defer remove()
+	}
+
+	poll := func() (*types.PipeConnectionMap, bool, error) {
+		r, err := c.Get(ctx, roomId, opts...)
+		if err != nil {
+			return nil, false, err
+		}
+		if r.State != state {
+			return nil, false, nil
+		}
+		return connectionMapFromRoom(r), true, nil
+	}
+	if m, ok, err := poll(); err != nil || ok {
+		return m, err
+	}
+	lastPoll := time.Now()
+
+	ticker := time.NewTicker(roomPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case e := <-events:
+			if r, m, _ := roomFromEvent(e, roomId); r.State == state {
+				return m, nil
+			}
+		case <-ticker.C:
+			if l != nil && l.State().ConnectionState == types.LeapConnectionStateConnected &&
+				atomic.SwapUint32(&dropped, 0) == 0 && time.Since(lastPoll) < roomLeapPollInterval {
+				// Leap is connected and no events were dropped, so there is no need to poll yet.
+				continue
+			}
+			if m, ok, err := poll(); err != nil || ok {
+				return m, err
+			}
+			lastPoll = time.Now()
+		}
+	}
+}
+
+// WaitLive is used to wait for a room to go live, for example when a broadcaster starts streaming. Returns the
+// connection information which clients can use to play the room. Use WithLeapClient to get notified by Leap rather
+// than polling the room every 5 seconds. The serving POP of the connections is only set when the update came from Leap.
+func (c ClientCategoryPipeRooms) WaitLive(
+	ctx context.Context, roomId string, opts ...ClientOption,
+) (*types.PipeConnectionMap, error) {
+	return c.waitState(ctx, roomId, types.RoomStateLive, opts)
+}
+
+// WaitOffline is used to wait for a room to go offline, for example when a broadcaster stops streaming. Use
+// WithLeapClient to get notified by Leap rather than polling the room every 5 seconds.
+func (c ClientCategoryPipeRooms) WaitOffline(ctx context.Context, roomId string, opts ...ClientOption) error {
+	_, err := c.waitState(ctx, roomId, types.RoomStateOffline, opts)
+	return err
+}
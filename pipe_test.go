@@ -1,8 +1,13 @@
 package hop
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.hop.io/sdk/types"
 )
 
@@ -40,6 +45,18 @@ func TestClient_Pipe_Rooms_Create(t *testing.T) {
 		&types.Room{Name: "hello"})
 }
 
+func TestClient_Pipe_Rooms_Create_Invalid(t *testing.T) {
+	c := &funcClientDoer{tokenType: "pat", fn: func(ClientArgs, []ClientOption) error {
+		t.Fatal("the request should not be sent")
+		return nil
+	}}
+	_, err := ClientCategoryPipeRooms{c: c}.Create(context.Background(), types.RoomCreationOptions{
+		IngestProtocol:    types.IngestProtocolWHIP,
+		DeliveryProtocols: []types.DeliveryProtocol{types.DeliveryProtocolHLS},
+	})
+	assert.EqualError(t, err, "hls delivery is not supported with whip ingest")
+}
+
 func TestClient_Pipe_Rooms_Delete(t *testing.T) {
 	c := &mockClientDoer{
 		t:              t,
@@ -55,3 +72,279 @@ func TestClient_Pipe_Rooms_Delete(t *testing.T) {
 		[]any{"test test", WithProjectID("test123")},
 		nil)
 }
+
+func TestClient_Pipe_Rooms_Get(t *testing.T) {
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "GET",
+		wantPath:       "/pipe/rooms/test%20test",
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		wantResultKey:  "room",
+		wantIgnore404:  false,
+		tokenType:      "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryPipeRooms{c: c},
+		"Get",
+		[]any{"test test", WithProjectID("test123")},
+		&types.Room{Name: "hello"})
+}
+
+func TestClient_Pipe_Rooms_Update(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "PATCH",
+		wantPath:      "/pipe/rooms/test%20test",
+		wantResultKey: "room",
+		wantIgnore404: false,
+		wantBody: types.RoomUpdateOptions{
+			Name:              "test",
+			DeliveryProtocols: []types.DeliveryProtocol{types.DeliveryProtocolHLS},
+			HLSConfig:         &types.HLSConfig{WCLDelay: 2},
+		},
+		tokenType: "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryPipeRooms{c: c},
+		"Update",
+		[]any{"test test", types.RoomUpdateOptions{
+			Name:              "test",
+			DeliveryProtocols: []types.DeliveryProtocol{types.DeliveryProtocolHLS},
+			HLSConfig:         &types.HLSConfig{WCLDelay: 2},
+		}},
+		&types.Room{Name: "hello"})
+}
+
+func TestClient_Pipe_Rooms_Update_Invalid(t *testing.T) {
+	c := &funcClientDoer{tokenType: "pat", fn: func(ClientArgs, []ClientOption) error {
+		t.Fatal("the request should not be sent")
+		return nil
+	}}
+	_, err := ClientCategoryPipeRooms{c: c}.Update(context.Background(), "test", types.RoomUpdateOptions{
+		HLSConfig: &types.HLSConfig{ArtificialDelay: -1},
+	})
+	assert.EqualError(t, err, "artificial delay must not be negative")
+}
+
+func TestClient_Pipe_Rooms_RegenerateStreamKey(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "POST",
+		wantPath:      "/pipe/rooms/test%20test/stream-key",
+		wantResultKey: "room",
+		wantIgnore404: false,
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryPipeRooms{c: c},
+		"RegenerateStreamKey",
+		[]any{"test test"},
+		&types.Room{StreamKey: "hello"})
+}
+
+func TestClient_Pipe_Rooms_RegenerateJoinToken(t *testing.T) {
+	c := &mockClientDoer{
+		t:             t,
+		wantMethod:    "POST",
+		wantPath:      "/pipe/rooms/test%20test/join-token",
+		wantResultKey: "room",
+		wantIgnore404: false,
+		tokenType:     "pat",
+	}
+	testApiSingleton(c,
+		&ClientCategoryPipeRooms{c: c},
+		"RegenerateJoinToken",
+		[]any{"test test"},
+		&types.Room{JoinToken: "hello"})
+}
+
+func TestClient_Pipe_Rooms_WaitLive(t *testing.T) {
+	defer func(d time.Duration) { roomPollInterval = d }(roomPollInterval)
+	roomPollInterval = time.Millisecond
+
+	calls := 0
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		assert.Equal(t, "/pipe/rooms/room_1", a.Path)
+		calls++
+		state := types.RoomStateOffline
+		if calls == 3 {
+			state = types.RoomStateLive
+		}
+		setResult(a, types.Room{
+			ID:                "room_1",
+			State:             state,
+			IngestRegion:      types.RegionUSEast1,
+			DeliveryProtocols: []types.DeliveryProtocol{types.DeliveryProtocolHLS},
+		})
+		return nil
+	}}
+	m, err := ClientCategoryPipeRooms{c: c}.WaitLive(context.Background(), "room_1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, &types.PipeConnectionMap{LLHLS: &types.PipeConnection{
+		EdgeEndpoint: "https://us-east-1.playback.hop.io/hls/room_1/index.m3u8",
+		Type:         types.DeliveryProtocolHLS,
+	}}, m)
+
+	// The room is already offline, so this should return straight away.
+	calls = 0
+	err = ClientCategoryPipeRooms{c: c}.WaitOffline(context.Background(), "room_1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_Pipe_Rooms_WaitLive_Cancel(t *testing.T) {
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		setResult(a, types.Room{ID: "room_1", State: types.RoomStateOffline})
+		return nil
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := ClientCategoryPipeRooms{c: c}.WaitLive(ctx, "room_1")
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+// Defines a stand-in for a Leap client which sends events to the listeners when dispatch is called.
+type fakeLeapSource struct {
+	mu        sync.Mutex
+	state     types.LeapConnectionState
+	listeners []func(types.LeapChannelEvent)
+}
+
+func (f *fakeLeapSource) AddChannelEventListener(fn func(types.LeapChannelEvent)) func() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.listeners = append(f.listeners, fn)
+	return func() {}
+}
+
+func (f *fakeLeapSource) State() types.LeapStateInfo {
+	return types.LeapStateInfo{ConnectionState: f.state}
+}
+
+func (f *fakeLeapSource) dispatch(e types.LeapChannelEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, fn := range f.listeners {
+		fn(e)
+	}
+}
+
+func TestClient_Pipe_Rooms_WaitLive_Leap(t *testing.T) {
+	defer func(d, leapD time.Duration) {
+		roomPollInterval = d
+		roomLeapPollInterval = leapD
+	}(roomPollInterval, roomLeapPollInterval)
+	roomPollInterval = time.Millisecond
+
+	conn := types.PipeConnectionMap{WebRTC: &types.PipeConnection{EdgeEndpoint: "https://example.com"}}
+	polled := &types.PipeConnectionMap{LLHLS: &types.PipeConnection{
+		EdgeEndpoint: "https://us-east-1.playback.hop.io/hls/room_1/index.m3u8",
+		Type:         types.DeliveryProtocolHLS,
+	}}
+	tests := []struct {
+		name string
+
+		state            types.LeapConnectionState
+		leapPollInterval time.Duration
+		events           []types.LeapChannelEvent
+		wantCalls        int
+		want             *types.PipeConnectionMap
+	}{
+		{
+			name:             "live event",
+			state:            types.LeapConnectionStateConnected,
+			leapPollInterval: time.Hour,
+			events: []types.LeapChannelEvent{
+				types.LeapPipeRoomUpdateEvent{PipeRoom: types.Room{ID: "room_2", State: types.RoomStateLive}},
+				types.LeapPipeRoomUpdateEvent{PipeRoom: types.Room{ID: "room_1", State: types.RoomStateLive}, Connection: conn},
+			},
+			wantCalls: 1,
+			want:      &conn,
+		},
+		{
+			name:             "dropped events",
+			state:            types.LeapConnectionStateConnected,
+			leapPollInterval: time.Hour,
+			events: func() []types.LeapChannelEvent {
+				e := make([]types.LeapChannelEvent, 20)
+				for i := range e {
+					e[i] = types.LeapPipeRoomUpdateEvent{PipeRoom: types.Room{ID: "room_1", State: types.RoomStateOffline}}
+				}
+				return e
+			}(),
+			wantCalls: 2,
+			want:      polled,
+		},
+		{
+			name:             "safety net poll",
+			state:            types.LeapConnectionStateConnected,
+			leapPollInterval: time.Millisecond,
+			wantCalls:        2,
+			want:             polled,
+		},
+		{
+			name:             "not connected",
+			state:            types.LeapConnectionStateConnecting,
+			leapPollInterval: time.Hour,
+			wantCalls:        2,
+			want:             polled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roomLeapPollInterval = tt.leapPollInterval
+			l := &fakeLeapSource{state: tt.state}
+			calls := 0
+			c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+				calls++
+				state := types.RoomStateOffline
+				if calls == 1 {
+					// Send the events after the listener is added, but before the wait loop starts.
+					for _, e := range tt.events {
+						l.dispatch(e)
+					}
+				} else {
+					state = types.RoomStateLive
+				}
+				setResult(a, types.Room{
+					ID:                "room_1",
+					State:             state,
+					IngestRegion:      types.RegionUSEast1,
+					DeliveryProtocols: []types.DeliveryProtocol{types.DeliveryProtocolHLS},
+				})
+				return nil
+			}}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			m, err := ClientCategoryPipeRooms{c: c}.WaitLive(ctx, "room_1", leapClientOption{c: l})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.want, m)
+		})
+	}
+}
+
+func TestWithLeapClient_Nil(t *testing.T) {
+	assert.Nil(t, getLeapClient([]ClientOption{WithLeapClient(nil)}))
+}
+
+func Test_roomFromEvent(t *testing.T) {
+	conn := types.PipeConnectionMap{WebRTC: &types.PipeConnection{EdgeEndpoint: "https://example.com"}}
+	room := types.Room{ID: "room_1", State: types.RoomStateLive}
+
+	r, m, ok := roomFromEvent(types.LeapPipeRoomAvailableEvent{PipeRoom: room, Connection: conn}, "room_1")
+	assert.True(t, ok)
+	assert.Equal(t, &room, r)
+	assert.Equal(t, &conn, m)
+
+	r, m, ok = roomFromEvent(types.LeapPipeRoomUpdateEvent{PipeRoom: room, Connection: conn}, "room_1")
+	assert.True(t, ok)
+	assert.Equal(t, &room, r)
+	assert.Equal(t, &conn, m)
+
+	_, _, ok = roomFromEvent(types.LeapPipeRoomAvailableEvent{PipeRoom: room}, "room_2")
+	assert.False(t, ok)
+	_, _, ok = roomFromEvent(types.LeapChannelStateUpdateEvent{}, "room_1")
+	assert.False(t, ok)
+}
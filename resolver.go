@@ -0,0 +1,154 @@
+package hop
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.hop.io/sdk/types"
+)
+
+// ResolverHostSuffix is the suffix of hostnames which the resolver dials as gateways. The hostname
+// "<gateway name>.<deployment name>.ignite" is the same as "<deployment name>/<gateway name>", which allows gateways
+// to be used in URLs.
+const ResolverHostSuffix = ".ignite"
+
+// ResolverConfig is used to define the configuration for a Resolver.
+type ResolverConfig struct {
+	// TTL is how long the gateways of a deployment are cached for. If this is 0, it will default to 1 minute.
+	TTL time.Duration
+
+	// Clock is the clock used to expire the cache. If this is nil, the system clock is used.
+	Clock Clock
+
+	// Dialer is the dialer used by DialContext. If this is nil, a zero value net.Dialer is used.
+	Dialer *net.Dialer
+}
+
+// Defines the cached gateways of a deployment.
+type resolverEntry struct {
+	gateways []*types.Gateway
+	expires  time.Time
+}
+
+// Resolver is used to resolve the internal addresses of gateways by the deployment and gateway name. The results are
+// cached, so this should be shared. Please use NewResolver to create this.
+type Resolver struct {
+	d    ClientCategoryIgniteDeployments
+	opts []ClientOption
+	cfg  ResolverConfig
+
+	mu    sync.Mutex
+	cache map[string]resolverEntry
+}
+
+// NewResolver is used to create a resolver for the internal gateways of the deployments within a project. The
+// resolver can be used with net/http by setting DialContext on the transport:
+//
+//	r := c.Ignite.Deployments.NewResolver(hop.ResolverConfig{})
+//	client := &http.Client{Transport: &http.Transport{DialContext: r.DialContext}}
+//	res, err := client.Get("http://my-gateway.my-deployment.ignite/")
+func (c ClientCategoryIgniteDeployments) NewResolver(cfg ResolverConfig, opts ...ClientOption) *Resolver {
+	if cfg.TTL == 0 {
+		cfg.TTL = time.Minute
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock{}
+	}
+	if cfg.Dialer == nil {
+		cfg.Dialer = &net.Dialer{}
+	}
+	return &Resolver{d: c, opts: opts, cfg: cfg, cache: map[string]resolverEntry{}}
+}
+
+// Returns the gateways of the deployment specified, using the cache if it has not expired.
+func (r *Resolver) gateways(ctx context.Context, deploymentName string) ([]*types.Gateway, error) {
+	r.mu.Lock()
+	e, ok := r.cache[deploymentName]
+	r.mu.Unlock()
+	if ok && r.cfg.Clock.Now().Before(e.expires) {
+		return e.gateways, nil
+	}
+
+	d, err := r.d.GetByName(ctx, deploymentName, r.opts...)
+	if err != nil {
+		return nil, err
+	}
+	gateways, err := r.d.GetAllGateways(ctx, d.ID, r.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[deploymentName] = resolverEntry{gateways: gateways, expires: r.cfg.Clock.Now().Add(r.cfg.TTL)}
+	r.mu.Unlock()
+	return gateways, nil
+}
+
+// Resolve is used to get the dialable host:port of a gateway from a name in the format
+// "<deployment name>/<gateway name>".
+func (r *Resolver) Resolve(ctx context.Context, name string) (string, error) {
+	deploymentName, gatewayName, ok := strings.Cut(name, "/")
+	if !ok || deploymentName == "" || gatewayName == "" {
+		return "", errors.New("gateway name must be in the format <deployment name>/<gateway name>: " + name)
+	}
+
+	gateways, err := r.gateways(ctx, deploymentName)
+	if err != nil {
+		return "", err
+	}
+	for _, gw := range gateways {
+		if gw.Name != gatewayName {
+			continue
+		}
+		if gw.InternalDomain == "" || gw.TargetPort == nil {
+			return "", errors.New("gateway " + name + " does not have an internal address")
+		}
+		return net.JoinHostPort(gw.InternalDomain, strconv.Itoa(*gw.TargetPort)), nil
+	}
+	return "", types.NotFound{Code: "gateway_not_found", Message: "gateway " + name + " was not found"}
+}
+
+// Invalidate is used to remove the cached gateways of a deployment, for example after a gateway is changed.
+func (r *Resolver) Invalidate(deploymentName string) {
+	r.mu.Lock()
+	delete(r.cache, deploymentName)
+	r.mu.Unlock()
+}
+
+// Returns the gateway name in the format "<deployment name>/<gateway name>" for the address specified. Returns false
+// if the address is not for a gateway.
+func gatewayNameFromAddress(address string) (string, bool) {
+	if strings.Contains(address, "/") {
+		return address, true
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if !strings.HasSuffix(host, ResolverHostSuffix) {
+		return "", false
+	}
+	gatewayName, deploymentName, ok := strings.Cut(strings.TrimSuffix(host, ResolverHostSuffix), ".")
+	if !ok {
+		return "", false
+	}
+	return deploymentName + "/" + gatewayName, true
+}
+
+// DialContext is used to dial a gateway. The address can either be in the format "<deployment name>/<gateway name>"
+// or a host:port where the host is "<gateway name>.<deployment name>.ignite", in which case the port is ignored. Any
+// other address is dialed as normal, so this can be used as the DialContext of a http.Transport.
+func (r *Resolver) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if name, ok := gatewayNameFromAddress(address); ok {
+		var err error
+		if address, err = r.Resolve(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+	return r.cfg.Dialer.DialContext(ctx, network, address)
+}
@@ -0,0 +1,131 @@
+package hop
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+// Returns a resolver where the deployment "api" has the gateways specified. The number of API calls is written to the
+// pointer returned.
+func newTestResolver(t *testing.T, clock Clock, gateways []*types.Gateway) (*Resolver, *int) {
+	t.Helper()
+	calls := 0
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		calls++
+		switch a.Path {
+		case "/ignite/deployments/search":
+			if a.Query["name"] != "api" {
+				return types.NotFound{Code: "deployment_not_found", Message: "deployment not found"}
+			}
+			setResult(a, types.Deployment{ID: "deployment_1", Name: "api"})
+		case "/ignite/deployments/deployment_1/gateways":
+			setResult(a, gateways)
+		default:
+			t.Errorf("unexpected path %s", a.Path)
+		}
+		return nil
+	}}
+	return ClientCategoryIgniteDeployments{c: c}.NewResolver(ResolverConfig{Clock: clock}), &calls
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	port := 8080
+	clock := &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r, calls := newTestResolver(t, clock, []*types.Gateway{
+		{Name: "http", InternalDomain: "api.deployment_1.hop", TargetPort: &port},
+		{Name: "no-port", InternalDomain: "api.deployment_1.hop"},
+	})
+
+	tests := []struct {
+		name string
+
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "found", input: "api/http", want: "api.deployment_1.hop:8080"},
+		{
+			name:    "gateway not found",
+			input:   "api/grpc",
+			wantErr: types.NotFound{Code: "gateway_not_found", Message: "gateway api/grpc was not found"},
+		},
+		{
+			name:    "deployment not found",
+			input:   "web/http",
+			wantErr: types.NotFound{Code: "deployment_not_found", Message: "deployment not found"},
+		},
+		{
+			name:    "no internal address",
+			input:   "api/no-port",
+			wantErr: errors.New("gateway api/no-port does not have an internal address"),
+		},
+		{
+			name:    "invalid name",
+			input:   "api",
+			wantErr: errors.New("gateway name must be in the format <deployment name>/<gateway name>: api"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := r.Resolve(context.Background(), tt.input)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, addr)
+		})
+	}
+
+	// The gateways of api should have been fetched once, and web should have failed once.
+	assert.Equal(t, 3, *calls)
+
+	clock.advance(time.Minute)
+	_, err := r.Resolve(context.Background(), "api/http")
+	require.NoError(t, err)
+	assert.Equal(t, 5, *calls)
+
+	r.Invalidate("api")
+	_, err = r.Resolve(context.Background(), "api/http")
+	require.NoError(t, err)
+	assert.Equal(t, 7, *calls)
+}
+
+func TestResolver_DialContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	t.Cleanup(srv.Close)
+	host, portStr, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	r, _ := newTestResolver(t, nil, []*types.Gateway{{Name: "http", InternalDomain: host, TargetPort: &port}})
+	client := &http.Client{Transport: &http.Transport{DialContext: r.DialContext}}
+
+	for _, u := range []string{"http://http.api.ignite/", "http://http.api.ignite:1234/", srv.URL} {
+		t.Run(u, func(t *testing.T) {
+			res, getErr := client.Get(u)
+			require.NoError(t, getErr)
+			defer res.Body.Close()
+			b, readErr := io.ReadAll(res.Body)
+			require.NoError(t, readErr)
+			assert.Equal(t, "hello", string(b))
+		})
+	}
+
+	conn, err := r.DialContext(context.Background(), "tcp", "api/http")
+	require.NoError(t, err)
+	assert.NoError(t, conn.Close())
+
+	_, err = r.DialContext(context.Background(), "tcp", "grpc.api.ignite:80")
+	assert.Equal(t, types.NotFound{Code: "gateway_not_found", Message: "gateway api/grpc was not found"}, err)
+}
@@ -0,0 +1,153 @@
+package hop
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"go.hop.io/sdk/types"
+)
+
+// The number of times UpdateState will try to write the state before returning types.PreconditionFailed.
+const stateUpdateAttempts = 5
+
+// StateCategory is implemented by the categories which store state, which are Channels and Channels.Tokens. This is
+// used by GetState, SetState, and UpdateState.
+type StateCategory interface {
+	// Gets the raw state and the ETag of the object (or a blank string if the API did not return one).
+	getState(ctx context.Context, id string, opts []ClientOption) (json.RawMessage, string, error)
+
+	// Sets the state of the object. If the ETag is not blank, the write will fail with types.PreconditionFailed if
+	// the object was changed since the ETag was fetched.
+	setState(ctx context.Context, id string, state any, etag string, opts []ClientOption) error
+}
+
+// Defines the part of a channel or token which contains the state.
+type rawState struct {
+	State json.RawMessage `json:"state"`
+}
+
+// Returns the headers used to make a write conditional on the ETag specified.
+func ifMatchHeaders(etag string) map[string]string {
+	if etag == "" {
+		return nil
+	}
+	return map[string]string{"If-Match": etag}
+}
+
+func (c ClientCategoryChannels) getState(ctx context.Context, id string, opts []ClientOption) (json.RawMessage, string, error) {
+	var s rawState
+	h := http.Header{}
+	err := c.c.do(ctx, ClientArgs{
+		Method:          "GET",
+		Path:            "/channels/" + url.PathEscape(id),
+		ResultKey:       "channel",
+		Result:          &s,
+		Ignore404:       false,
+		ResponseHeaders: h,
+	}, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.State, h.Get("ETag"), nil
+}
+
+func (c ClientCategoryChannels) setState(ctx context.Context, id string, state any, etag string, opts []ClientOption) error {
+	return c.c.do(ctx, ClientArgs{
+		Method:    "PUT",
+		Path:      "/channels/" + url.PathEscape(id) + "/state",
+		Body:      state,
+		Ignore404: false,
+		Headers:   ifMatchHeaders(etag),
+	}, opts)
+}
+
+func (t ClientCategoryChannelsTokens) getState(ctx context.Context, id string, opts []ClientOption) (json.RawMessage, string, error) {
+	var s rawState
+	h := http.Header{}
+	err := t.c.do(ctx, ClientArgs{
+		Method:          "GET",
+		Path:            "/channels/tokens/" + url.PathEscape(id),
+		ResultKey:       "token",
+		Result:          &s,
+		Ignore404:       false,
+		ResponseHeaders: h,
+	}, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.State, h.Get("ETag"), nil
+}
+
+func (t ClientCategoryChannelsTokens) setState(
+	ctx context.Context, id string, state any, etag string, opts []ClientOption,
+) error {
+	return t.c.do(ctx, ClientArgs{
+		Method:    "PATCH",
+		Path:      "/channels/tokens/" + url.PathEscape(id),
+		Body:      map[string]any{"state": state},
+		Ignore404: false,
+		Headers:   ifMatchHeaders(etag),
+	}, opts)
+}
+
+// Unmarshals the raw state into the value specified. A null or missing state leaves the value as its zero value.
+func unmarshalState[T any](raw json.RawMessage, v *T) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// GetState is used to get the state of a channel or channel token and unmarshal it into T. The category should either
+// be Channels or Channels.Tokens:
+//
+//	state, err := hop.GetState[MyState](ctx, c.Channels, "channel_id")
+func GetState[T any](ctx context.Context, c StateCategory, id string, opts ...ClientOption) (*T, error) {
+	raw, _, err := c.getState(ctx, id, opts)
+	if err != nil {
+		return nil, err
+	}
+	var v T
+	if err = unmarshalState(raw, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// SetState is used to replace the state of a channel or channel token with the value specified, which should marshal
+// to a JSON object. This does not check if the state was changed by something else, use UpdateState for that.
+func SetState[T any](ctx context.Context, c StateCategory, id string, state T, opts ...ClientOption) error {
+	return c.setState(ctx, id, state, "", opts)
+}
+
+// UpdateState is used to do a read-modify-write of the state of a channel or channel token. The state is fetched and
+// passed to fn, and the result is written back. If fn returns an error, nothing is written and the error is returned.
+// If the API returns an ETag for the state, the write is only made if the state has not been changed since it was
+// fetched, otherwise fn is called again with the new state. This is tried up to 5 times before types.PreconditionFailed
+// is returned. If the API does not return an ETag, the last write wins. Returns the state which was written.
+func UpdateState[T any](ctx context.Context, c StateCategory, id string, fn func(*T) error, opts ...ClientOption) (*T, error) {
+	var err error
+	for i := 0; i < stateUpdateAttempts; i++ {
+		raw, etag, getErr := c.getState(ctx, id, opts)
+		if getErr != nil {
+			return nil, getErr
+		}
+		var v T
+		if err = unmarshalState(raw, &v); err != nil {
+			return nil, err
+		}
+		if err = fn(&v); err != nil {
+			return nil, err
+		}
+		err = c.setState(ctx, id, &v, etag, opts)
+		if err == nil {
+			return &v, nil
+		}
+		if _, ok := err.(types.PreconditionFailed); !ok {
+			return nil, err
+		}
+	}
+	return nil, err
+}
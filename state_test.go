@@ -0,0 +1,175 @@
+package hop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+type testState struct {
+	Count int    `json:"count"`
+	Name  string `json:"name,omitempty"`
+}
+
+func TestGetState(t *testing.T) {
+	tests := []struct {
+		name string
+
+		category func(c clientDoer) StateCategory
+		path     string
+		key      string
+	}{
+		{
+			name:     "channel",
+			category: func(c clientDoer) StateCategory { return ClientCategoryChannels{c: c} },
+			path:     "/channels/test%20test",
+			key:      "channel",
+		},
+		{
+			name:     "token",
+			category: func(c clientDoer) StateCategory { return ClientCategoryChannelsTokens{c: c} },
+			path:     "/channels/tokens/test%20test",
+			key:      "token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, opts []ClientOption) error {
+				assert.Equal(t, "GET", a.Method)
+				assert.Equal(t, tt.path, a.Path)
+				assert.Equal(t, tt.key, a.ResultKey)
+				assert.Equal(t, []ClientOption{WithProjectID("test123")}, opts)
+				setResult(a, rawState{State: []byte(`{"count":1,"name":"hello"}`)})
+				return nil
+			}}
+			s, err := GetState[testState](context.Background(), tt.category(c), "test test", WithProjectID("test123"))
+			require.NoError(t, err)
+			assert.Equal(t, &testState{Count: 1, Name: "hello"}, s)
+		})
+	}
+
+	c := &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		setResult(a, rawState{State: []byte("null")})
+		return nil
+	}}
+	s, err := GetState[testState](context.Background(), ClientCategoryChannels{c: c}, "test")
+	require.NoError(t, err)
+	assert.Equal(t, &testState{}, s)
+}
+
+func TestSetState(t *testing.T) {
+	c := &mockClientDoer{
+		t:              t,
+		wantMethod:     "PUT",
+		wantPath:       "/channels/test%20test/state",
+		wantClientOpts: []ClientOption{WithProjectID("test123")},
+		wantBody:       testState{Count: 1},
+		tokenType:      "pat",
+	}
+	require.NoError(t, SetState(context.Background(), ClientCategoryChannels{c: c}, "test test", testState{Count: 1},
+		WithProjectID("test123")))
+
+	c = &mockClientDoer{
+		t:          t,
+		wantMethod: "PATCH",
+		wantPath:   "/channels/tokens/test%20test",
+		wantBody:   map[string]any{"state": testState{Count: 1}},
+		tokenType:  "pat",
+	}
+	require.NoError(t, SetState(context.Background(), ClientCategoryChannelsTokens{c: c}, "test test", testState{Count: 1}))
+}
+
+// Returns a client which stores the state of a channel with an ETag which changes on every write. The concurrentWrite
+// function is called before each write, and should return true if it changed the state.
+func newStateTestClient(t *testing.T, concurrentWrite func(state *testState) bool) (*funcClientDoer, *testState) {
+	t.Helper()
+	state := &testState{}
+	version := 0
+	etag := func() string { return `"` + strconv.Itoa(version) + `"` }
+	return &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		switch a.Method {
+		case "GET":
+			a.ResponseHeaders.Set("ETag", etag())
+			b, err := json.Marshal(state)
+			require.NoError(t, err)
+			setResult(a, rawState{State: b})
+		case "PUT":
+			if concurrentWrite(state) {
+				version++
+			}
+			if a.Headers["If-Match"] != etag() {
+				return types.PreconditionFailed{Code: "precondition_failed", Message: "state was changed"}
+			}
+			body, ok := a.Body.(*testState)
+			require.True(t, ok)
+			*state = *body
+			version++
+		default:
+			t.Errorf("unexpected method %s", a.Method)
+		}
+		return nil
+	}}, state
+}
+
+func TestUpdateState(t *testing.T) {
+	conflicts := 2
+	c, state := newStateTestClient(t, func(s *testState) bool {
+		if conflicts == 0 {
+			return false
+		}
+		conflicts--
+		s.Count++
+		return true
+	})
+	calls := 0
+	s, err := UpdateState(context.Background(), ClientCategoryChannels{c: c}, "test", func(s *testState) error {
+		calls++
+		s.Count++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, &testState{Count: 3}, s)
+	assert.Equal(t, testState{Count: 3}, *state)
+
+	// Without an ETag, the state should be written without a precondition.
+	c.fn = func(a ClientArgs, _ []ClientOption) error {
+		if a.Method == "GET" {
+			setResult(a, rawState{})
+		} else {
+			assert.Nil(t, a.Headers)
+		}
+		return nil
+	}
+	s, err = UpdateState(context.Background(), ClientCategoryChannels{c: c}, "test", func(s *testState) error {
+		s.Name = "hello"
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &testState{Name: "hello"}, s)
+}
+
+func TestUpdateState_Errors(t *testing.T) {
+	c, state := newStateTestClient(t, func(s *testState) bool {
+		s.Count++
+		return true
+	})
+	_, err := UpdateState(context.Background(), ClientCategoryChannels{c: c}, "test", func(s *testState) error {
+		s.Count = 100
+		return nil
+	})
+	assert.Equal(t, types.PreconditionFailed{Code: "precondition_failed", Message: "state was changed"}, err)
+	assert.Equal(t, testState{Count: 5}, *state)
+
+	_, err = UpdateState(context.Background(), ClientCategoryChannels{c: c}, "test", func(*testState) error {
+		return errors.New("no thanks")
+	})
+	assert.EqualError(t, err, "no thanks")
+	assert.Equal(t, testState{Count: 5}, *state)
+}
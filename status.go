@@ -0,0 +1,148 @@
+package hop
+
+import (
+	"context"
+	"strconv"
+
+	"go.hop.io/sdk/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// The exit code of a process which was killed with SIGKILL, which is usually caused by running out of memory.
+const killedExitCode = 137
+
+// Returns hints about the container and whether it is ready.
+func containerHints(
+	c *types.Container, states []*types.HealthCheckState, checkTypes map[string]types.HealthCheckType,
+) ([]string, bool) {
+	var hints []string
+	ready := c.State == types.ContainerStateRunning
+	exitCode := c.Metadata.LastExitCode
+
+	switch c.State {
+	case types.ContainerStatePending:
+		hints = append(hints, "the container has not started yet")
+	case types.ContainerStateFailed, types.ContainerStateExited:
+		if exitCode != nil {
+			hints = append(hints, "the container exited with code "+strconv.Itoa(*exitCode)+", check the logs for the cause")
+		} else {
+			hints = append(hints, "the container has exited, check the logs for the cause")
+		}
+	case types.ContainerStateRunning:
+		if exitCode != nil && *exitCode != 0 {
+			hints = append(hints, "the container has restarted after exiting with code "+strconv.Itoa(*exitCode))
+		}
+	}
+	if exitCode != nil && *exitCode == killedExitCode {
+		hints = append(hints, "exit code 137 usually means the container was killed for using too much memory")
+	}
+
+	for _, s := range states {
+		if s.State == types.HealthCheckStatusSucceeded {
+			continue
+		}
+		ready = false
+		if s.State != types.HealthCheckStatusFailed {
+			continue
+		}
+		if checkTypes[s.HealthCheckID] == types.HealthCheckTypeLiveness {
+			hints = append(hints, "the liveness health check "+s.HealthCheckID+" is failing, so the container will be restarted")
+		} else {
+			hints = append(hints, "the readiness health check "+s.HealthCheckID+" is failing, so the container will not receive traffic")
+		}
+	}
+	return hints, ready
+}
+
+// Builds the status report from the deployment information.
+func buildDeploymentStatus(
+	d *types.Deployment, checks []*types.HealthCheck, states []*types.HealthCheckState, containers []*types.Container,
+) *types.DeploymentStatus {
+	checkTypes := make(map[string]types.HealthCheckType, len(checks))
+	for _, v := range checks {
+		checkTypes[v.ID] = v.Type
+	}
+	statesByContainer := map[string][]*types.HealthCheckState{}
+	for _, v := range states {
+		statesByContainer[v.ContainerID] = append(statesByContainer[v.ContainerID], v)
+	}
+
+	report := &types.DeploymentStatus{
+		DeploymentID:  d.ID,
+		Containers:    make([]*types.ContainerHealth, len(containers)),
+		HealthChecks:  checks,
+		LatestRollout: d.LatestRollout,
+	}
+	readyCount := 0
+	for i, c := range containers {
+		containerStates := statesByContainer[c.ID]
+		hints, ready := containerHints(c, containerStates, checkTypes)
+		if ready {
+			readyCount++
+		}
+		report.Containers[i] = &types.ContainerHealth{
+			ContainerID:  c.ID,
+			State:        c.State,
+			Ready:        ready,
+			LastExitCode: c.Metadata.LastExitCode,
+			LastStart:    c.Uptime.LastStart,
+			HealthChecks: containerStates,
+			Hints:        hints,
+		}
+	}
+
+	rolloutFailed := d.LatestRollout != nil && d.LatestRollout.HealthCheckFailed
+	if rolloutFailed {
+		report.Hints = append(report.Hints, "the latest rollout failed its health checks")
+	}
+	switch {
+	case len(containers) == 0:
+		report.Verdict = types.DeploymentVerdictDown
+		report.Hints = append(report.Hints, "the deployment has no containers")
+	case readyCount == 0:
+		report.Verdict = types.DeploymentVerdictDown
+	case readyCount < len(containers) || rolloutFailed:
+		report.Verdict = types.DeploymentVerdictDegraded
+	default:
+		report.Verdict = types.DeploymentVerdictReady
+	}
+	return report
+}
+
+// Status is used to get a consolidated report of the health of a deployment. The deployment, its health checks, their
+// states and the containers are fetched concurrently.
+func (c ClientCategoryIgniteDeployments) Status(
+	ctx context.Context, id string, opts ...ClientOption,
+) (*types.DeploymentStatus, error) {
+	var (
+		d          *types.Deployment
+		checks     []*types.HealthCheck
+		states     []*types.HealthCheckState
+		containers []*types.Container
+	)
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		var err error
+		d, err = c.Get(egCtx, id, opts...)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		checks, err = c.GetHealthChecks(egCtx, id, opts...)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		states, err = c.HealthCheckStates(egCtx, id, opts...)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		containers, err = c.GetContainers(egCtx, id, opts...)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return buildDeploymentStatus(d, checks, states, containers), nil
+}
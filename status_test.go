@@ -0,0 +1,186 @@
+package hop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+// Returns a client which serves the deployment information specified. Any error is returned for the containers.
+func newStatusTestClient(
+	t *testing.T, d types.Deployment, checks []*types.HealthCheck, states []*types.HealthCheckState,
+	containers []*types.Container, err error,
+) ClientCategoryIgniteDeployments {
+	t.Helper()
+	return ClientCategoryIgniteDeployments{c: &funcClientDoer{tokenType: "pat", fn: func(a ClientArgs, _ []ClientOption) error {
+		switch a.Path {
+		case "/ignite/deployments/deployment_1":
+			setResult(a, d)
+		case "/ignite/deployments/deployment_1/health-checks":
+			setResult(a, checks)
+		case "/ignite/deployments/deployment_1/health-check-state":
+			setResult(a, states)
+		case "/ignite/deployments/deployment_1/containers":
+			if err != nil {
+				return err
+			}
+			setResult(a, containers)
+		default:
+			t.Errorf("unexpected path %s", a.Path)
+		}
+		return nil
+	}}}
+}
+
+func TestClient_Ignite_Deployments_Status(t *testing.T) {
+	exitCode := 137
+	checks := []*types.HealthCheck{
+		{ID: "health_check_live", Type: types.HealthCheckTypeLiveness},
+		{ID: "health_check_ready", Type: types.HealthCheckTypeReadiness},
+	}
+
+	tests := []struct {
+		name string
+
+		deployment types.Deployment
+		states     []*types.HealthCheckState
+		containers []*types.Container
+		want       *types.DeploymentStatus
+	}{
+		{
+			name:       "ready",
+			deployment: types.Deployment{ID: "deployment_1"},
+			states: []*types.HealthCheckState{
+				{ContainerID: "container_1", HealthCheckID: "health_check_ready", State: types.HealthCheckStatusSucceeded},
+			},
+			containers: []*types.Container{{ID: "container_1", State: types.ContainerStateRunning}},
+			want: &types.DeploymentStatus{
+				DeploymentID: "deployment_1",
+				Verdict:      types.DeploymentVerdictReady,
+				Containers: []*types.ContainerHealth{{
+					ContainerID: "container_1",
+					State:       types.ContainerStateRunning,
+					Ready:       true,
+					HealthChecks: []*types.HealthCheckState{
+						{ContainerID: "container_1", HealthCheckID: "health_check_ready", State: types.HealthCheckStatusSucceeded},
+					},
+				}},
+				HealthChecks: checks,
+			},
+		},
+		{
+			name: "degraded",
+			deployment: types.Deployment{
+				ID:            "deployment_1",
+				LatestRollout: &types.DeploymentRollout{ID: "rollout_1", HealthCheckFailed: true},
+			},
+			states: []*types.HealthCheckState{
+				{ContainerID: "container_2", HealthCheckID: "health_check_live", State: types.HealthCheckStatusFailed},
+				{ContainerID: "container_3", HealthCheckID: "health_check_ready", State: types.HealthCheckStatusFailed},
+			},
+			containers: []*types.Container{
+				{
+					ID:       "container_1",
+					State:    types.ContainerStateRunning,
+					Metadata: types.ContainerMetadata{LastExitCode: &exitCode},
+					Uptime:   types.ContainerUptime{LastStart: "2022-01-01T00:00:00Z"},
+				},
+				{ID: "container_2", State: types.ContainerStateRunning},
+				{ID: "container_3", State: types.ContainerStateRunning},
+				{ID: "container_4", State: types.ContainerStatePending},
+			},
+			want: &types.DeploymentStatus{
+				DeploymentID: "deployment_1",
+				Verdict:      types.DeploymentVerdictDegraded,
+				Containers: []*types.ContainerHealth{
+					{
+						ContainerID:  "container_1",
+						State:        types.ContainerStateRunning,
+						Ready:        true,
+						LastExitCode: &exitCode,
+						LastStart:    "2022-01-01T00:00:00Z",
+						Hints: []string{
+							"the container has restarted after exiting with code 137",
+							"exit code 137 usually means the container was killed for using too much memory",
+						},
+					},
+					{
+						ContainerID: "container_2",
+						State:       types.ContainerStateRunning,
+						HealthChecks: []*types.HealthCheckState{
+							{ContainerID: "container_2", HealthCheckID: "health_check_live", State: types.HealthCheckStatusFailed},
+						},
+						Hints: []string{"the liveness health check health_check_live is failing, so the container will be restarted"},
+					},
+					{
+						ContainerID: "container_3",
+						State:       types.ContainerStateRunning,
+						HealthChecks: []*types.HealthCheckState{
+							{ContainerID: "container_3", HealthCheckID: "health_check_ready", State: types.HealthCheckStatusFailed},
+						},
+						Hints: []string{
+							"the readiness health check health_check_ready is failing, so the container will not receive traffic",
+						},
+					},
+					{
+						ContainerID: "container_4",
+						State:       types.ContainerStatePending,
+						Hints:       []string{"the container has not started yet"},
+					},
+				},
+				HealthChecks:  checks,
+				LatestRollout: &types.DeploymentRollout{ID: "rollout_1", HealthCheckFailed: true},
+				Hints:         []string{"the latest rollout failed its health checks"},
+			},
+		},
+		{
+			name:       "down",
+			deployment: types.Deployment{ID: "deployment_1"},
+			containers: []*types.Container{
+				{ID: "container_1", State: types.ContainerStateFailed, Metadata: types.ContainerMetadata{LastExitCode: &exitCode}},
+			},
+			want: &types.DeploymentStatus{
+				DeploymentID: "deployment_1",
+				Verdict:      types.DeploymentVerdictDown,
+				Containers: []*types.ContainerHealth{{
+					ContainerID:  "container_1",
+					State:        types.ContainerStateFailed,
+					LastExitCode: &exitCode,
+					Hints: []string{
+						"the container exited with code 137, check the logs for the cause",
+						"exit code 137 usually means the container was killed for using too much memory",
+					},
+				}},
+				HealthChecks: checks,
+			},
+		},
+		{
+			name:       "no containers",
+			deployment: types.Deployment{ID: "deployment_1"},
+			want: &types.DeploymentStatus{
+				DeploymentID: "deployment_1",
+				Verdict:      types.DeploymentVerdictDown,
+				Containers:   []*types.ContainerHealth{},
+				HealthChecks: checks,
+				Hints:        []string{"the deployment has no containers"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newStatusTestClient(t, tt.deployment, checks, tt.states, tt.containers, nil)
+			status, err := d.Status(context.Background(), "deployment_1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, status)
+		})
+	}
+}
+
+func TestClient_Ignite_Deployments_Status_Error(t *testing.T) {
+	d := newStatusTestClient(t, types.Deployment{ID: "deployment_1"}, nil, nil, nil, types.ServerError("boom"))
+	_, err := d.Status(context.Background(), "deployment_1")
+	assert.Equal(t, types.ServerError("boom"), err)
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // ChannelType is used to define the type of the Hop channel.
 type ChannelType string
 
@@ -59,4 +61,47 @@ type ChannelToken struct {
 
 	// IsOnline is whether the token is online (e.g.: active heartbeat and connected to leap).
 	IsOnline bool `json:"is_online"`
+
+	// ExpiresAt is when the token expires. This is nil if the token does not expire.
+	ExpiresAt *Timestamp `json:"expires_at"`
+}
+
+// Expired is used to check if the token has expired at the time specified. A token without an expiry (or with an
+// expiry which cannot be parsed) never expires.
+func (t ChannelToken) Expired(now time.Time) bool {
+	if t.ExpiresAt == nil {
+		return false
+	}
+	expiresAt, err := t.ExpiresAt.Time()
+	if err != nil {
+		return false
+	}
+	return !now.Before(expiresAt)
+}
+
+// ChannelMessage is used to define a message which was published to a channel.
+type ChannelMessage struct {
+	// ID is the ID of the message.
+	ID string `json:"id"`
+
+	// ChannelID is the ID of the channel the message was published to.
+	ChannelID string `json:"channel_id"`
+
+	// EventName is the name of the event.
+	EventName string `json:"e"`
+
+	// Data is the user provided event data for the message.
+	Data map[string]any `json:"d"`
+
+	// CreatedAt is when the message was published.
+	CreatedAt Timestamp `json:"created_at"`
+}
+
+// ChannelTokenCreationOptions is used to define the options for creating a channel token.
+type ChannelTokenCreationOptions struct {
+	// State is the state of the token. If this is nil, the token will have an empty state.
+	State map[string]any `json:"state"`
+
+	// ExpiresIn is how long the token is valid for. If this is 0, the token does not expire.
+	ExpiresIn Seconds `json:"expires_in,omitempty"`
 }
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelToken_Expired(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := TimestampFromTime(now.Add(time.Minute))
+	invalid := Timestamp("soon")
+
+	assert.False(t, ChannelToken{}.Expired(now))
+	assert.False(t, ChannelToken{ExpiresAt: &expiresAt}.Expired(now))
+	assert.True(t, ChannelToken{ExpiresAt: &expiresAt}.Expired(now.Add(time.Minute)))
+	assert.False(t, ChannelToken{ExpiresAt: &invalid}.Expired(now))
+}
@@ -52,6 +52,18 @@ func (u UnknownServerError) Error() string {
 	return "status code " + strconv.Itoa(u.StatusCode) + " (" + u.Code + "): " + u.Message
 }
 
+// PreconditionFailed is sent when a conditional request fails because the object was changed since it was fetched.
+type PreconditionFailed struct {
+	// Code gives you the error code, allowing you to differentiate errors.
+	Code string `json:"code"`
+
+	// Message is the message from the Hop SDK.
+	Message string `json:"message"`
+}
+
+// Error implements the error interface.
+func (p PreconditionFailed) Error() string { return p.Message }
+
 // InvalidToken is thrown when the authentication token is invalid for the action you are attempting.
 type InvalidToken string
 
@@ -19,7 +19,8 @@ var types = []string{
 	"Build", "BuildMethod", "BuildMetadata", "BuildState", "IgniteGatewayUpdateOpts",
 	"HealthCheck", "HealthCheckCreateOpts", "HealthCheckState", "DeploymentStorageSize",
 	"DeploymentStorageInfo", "Deployment", "DeploymentMetadata", "Domain", "DomainRedirect",
-	"SelfUser",
+	"SelfUser", "VolumeResize", "VolumeSnapshot", "ContainerHealth", "DeploymentStatus",
+	"LeapContainerUpdateEvent", "LeapRolloutUpdateEvent", "LeapBuildUpdateEvent", "LeapHealthCheckUpdateEvent",
 }
 
 const stringTemplate = `// String returns the string representation of this value. This function is auto-generated.
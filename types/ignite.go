@@ -4,6 +4,9 @@ import (
 	_ "embed"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
@@ -314,8 +317,42 @@ type GatewayProtocol string
 const (
 	// GatewayProtocolHTTP is used to define a gateway that uses the HTTP protocol.
 	GatewayProtocolHTTP GatewayProtocol = "http"
+
+	// GatewayProtocolTCP is used to define a gateway that forwards raw TCP connections. These are exposed on their own
+	// external port.
+	GatewayProtocolTCP GatewayProtocol = "tcp"
+
+	// GatewayProtocolUDP is used to define a gateway that forwards UDP packets. These are exposed on their own external
+	// port.
+	GatewayProtocolUDP GatewayProtocol = "udp"
+
+	// GatewayProtocolGRPC is used to define a gateway that uses gRPC over HTTP/2.
+	GatewayProtocolGRPC GatewayProtocol = "grpc"
 )
 
+// Returns an error if the protocol is not known.
+func (x GatewayProtocol) validate() error {
+	switch x {
+	case GatewayProtocolHTTP, GatewayProtocolTCP, GatewayProtocolUDP, GatewayProtocolGRPC:
+		return nil
+	default:
+		return errors.New("unknown gateway protocol: " + string(x))
+	}
+}
+
+// HasExternalPort returns true if gateways using this protocol are exposed on their own external port.
+func (x GatewayProtocol) HasExternalPort() bool {
+	return x == GatewayProtocolTCP || x == GatewayProtocolUDP
+}
+
+// Returns an error if the port is not a valid port. The name is used in the error.
+func validatePort(name string, port int) error {
+	if port < 1 || port > 65535 {
+		return errors.New(name + " must be between 1 and 65535")
+	}
+	return nil
+}
+
 // DomainState is the state of the domain.
 type DomainState string
 
@@ -357,6 +394,32 @@ type DomainRedirect struct {
 	StatusCode int `json:"status_code"`
 }
 
+// DNSRecordType is used to define the type of a DNS record.
+type DNSRecordType string
+
+const (
+	// DNSRecordTypeCNAME is used to define a CNAME record.
+	DNSRecordTypeCNAME DNSRecordType = "CNAME"
+
+	// DNSRecordTypeA is used to define an A record.
+	DNSRecordTypeA DNSRecordType = "A"
+
+	// DNSRecordTypeAAAA is used to define an AAAA record.
+	DNSRecordTypeAAAA DNSRecordType = "AAAA"
+)
+
+// DNSRecord is used to define a DNS record which needs to be set for a domain.
+type DNSRecord struct {
+	// Type is the type of the record.
+	Type DNSRecordType `json:"type"`
+
+	// Name is the full name of the record.
+	Name string `json:"name"`
+
+	// Value is the value of the record.
+	Value string `json:"value"`
+}
+
 // Gateway is used to define a gateway used in Ignite.
 type Gateway struct {
 	// ID is used to define the ID of a gateway.
@@ -389,10 +452,23 @@ type Gateway struct {
 	// TargetPort is the port that this gateway is targeting. This will be nil if none is assigned.
 	TargetPort *int `json:"target_port"`
 
+	// ExternalPort is the port that TCP and UDP gateways are exposed on. This will be nil for other protocols or if
+	// none is assigned yet.
+	ExternalPort *int `json:"external_port"`
+
 	// Domains is the list of domains that this gateway is assigned to.
 	Domains []*Domain `json:"domains"`
 }
 
+// ExternalAddress returns the host:port which TCP and UDP gateways can be reached on from the internet. Returns a blank
+// string if the gateway does not have an external port or hop.sh domain.
+func (x Gateway) ExternalAddress() string {
+	if x.ExternalPort == nil || x.HopshDomain == "" {
+		return ""
+	}
+	return net.JoinHostPort(x.HopshDomain, strconv.Itoa(*x.ExternalPort))
+}
+
 // ContainerStrategy is the strategy used to scale a container.
 type ContainerStrategy string
 
@@ -896,12 +972,15 @@ type GatewayCreationOptions struct {
 	// Type is the type of gateway to create, either internal or external.
 	Type GatewayType `json:"type"`
 
-	// Protocol is the protocol to use for the gateway.
+	// Protocol is the protocol to use for the gateway. This must be blank for internal gateways.
 	Protocol GatewayProtocol `json:"protocol"`
 
-	// TargetPort is the port to listen on.
+	// TargetPort is the port to listen on. This is required for TCP, UDP and gRPC gateways.
 	TargetPort int `json:"target_port"`
 
+	// ExternalPort is the port to expose TCP and UDP gateways on. If this is 0, a port will be assigned.
+	ExternalPort int `json:"external_port,omitempty"`
+
 	// InternalDomain is used when the gateway type is internal.
 	InternalDomain string `json:"internal_domain,omitempty"`
 
@@ -911,6 +990,36 @@ type GatewayCreationOptions struct {
 	ProjectID string `json:"-"`
 }
 
+// Validate is used to check the protocol is known and the protocol and ports are a valid combination. A blank protocol
+// is treated as HTTP. This is called when the gateway is created.
+func (x GatewayCreationOptions) Validate() error {
+	if x.Protocol != "" {
+		if err := x.Protocol.validate(); err != nil {
+			return err
+		}
+	}
+	if x.TargetPort != 0 {
+		if err := validatePort("target port", x.TargetPort); err != nil {
+			return err
+		}
+	}
+	if x.ExternalPort != 0 {
+		if err := validatePort("external port", x.ExternalPort); err != nil {
+			return err
+		}
+		if x.Type == GatewayTypeInternal {
+			return errors.New("external port must not be set on internal gateways")
+		}
+		if !x.Protocol.HasExternalPort() {
+			return errors.New("external port can only be set on tcp and udp gateways")
+		}
+	}
+	if x.Protocol != "" && x.Protocol != GatewayProtocolHTTP && x.TargetPort == 0 {
+		return errors.New("target port must be set on " + string(x.Protocol) + " gateways")
+	}
+	return nil
+}
+
 // LoggingLevel is used to define the logging level.
 type LoggingLevel string
 
@@ -987,6 +1096,23 @@ type IgniteGatewayUpdateOpts struct {
 
 	// Protocol is the protocol to use for the gateway. If this is not blank, it will be updated.
 	Protocol GatewayProtocol `json:"protocol,omitempty"`
+
+	// HopshDomainEnabled is used to enable or disable the hop.sh domain. If this is not nil, it will be updated.
+	HopshDomainEnabled *bool `json:"hopsh_domain_enabled,omitempty"`
+}
+
+// Validate is used to check the protocol is known and the port is valid. This is called when the gateway is updated.
+// Since the other fields of the gateway are not known, the API may still reject the combination.
+func (x IgniteGatewayUpdateOpts) Validate() error {
+	if x.Protocol != "" {
+		if err := x.Protocol.validate(); err != nil {
+			return err
+		}
+	}
+	if x.TargetPort != 0 {
+		return validatePort("target port", x.TargetPort)
+	}
+	return nil
 }
 
 // HealthCheckProtocol is the type for a health check.
@@ -1055,6 +1181,32 @@ type HealthCheckCreateOpts struct {
 	MaxRetries int `json:"max_retries"`
 }
 
+// WithDefaults returns a copy of the options with the defaults that the API uses set on any blank fields.
+func (x HealthCheckCreateOpts) WithDefaults() HealthCheckCreateOpts {
+	if x.Protocol == "" {
+		x.Protocol = HealthCheckProtocolHTTP
+	}
+	if x.Path == "" {
+		x.Path = "/"
+	}
+	if x.Port == 0 {
+		x.Port = 8080
+	}
+	if x.InitialDelay == 0 {
+		x.InitialDelay = SecondsFromInt(5)
+	}
+	if x.Interval == 0 {
+		x.Interval = SecondsFromInt(60)
+	}
+	if x.Timeout == 0 {
+		x.Timeout = MillisecondsFromInt(50)
+	}
+	if x.MaxRetries == 0 {
+		x.MaxRetries = 3
+	}
+	return x
+}
+
 // HealthCheckType defines the type of the health check.
 type HealthCheckType string
 
@@ -1133,3 +1285,183 @@ type DeploymentStorageInfo struct {
 	// BuildCache is used to define the build cache storage information. Can be nil.
 	BuildCache *DeploymentStorageSize `json:"build_cache"`
 }
+
+// Provisioned returns the provisioned storage as a size.
+func (x DeploymentStorageSize) Provisioned() Size {
+	return Megabytes(x.ProvisionedSize)
+}
+
+// Used returns the used storage as a size.
+func (x DeploymentStorageSize) Used() Size {
+	return Megabytes(x.UsedSize)
+}
+
+// UsedFraction returns the fraction of the provisioned storage which is used. Returns 0 if nothing is provisioned.
+func (x DeploymentStorageSize) UsedFraction() float64 {
+	if x.ProvisionedSize <= 0 {
+		return 0
+	}
+	return float64(x.UsedSize) / float64(x.ProvisionedSize)
+}
+
+// StorageKind is used to define the kind of storage a warning relates to.
+type StorageKind string
+
+const (
+	// StorageKindVolume is used to define the volume of a deployment.
+	StorageKindVolume StorageKind = "volume"
+
+	// StorageKindBuildCache is used to define the build cache of a deployment.
+	StorageKindBuildCache StorageKind = "build_cache"
+)
+
+// StorageUsageWarning is used to define a warning that the usage of some storage has passed a threshold.
+type StorageUsageWarning struct {
+	// Kind is the kind of storage that this warning relates to.
+	Kind StorageKind `json:"kind"`
+
+	// Used is the amount of storage which is used.
+	Used Size `json:"used"`
+
+	// Provisioned is the amount of storage which is provisioned.
+	Provisioned Size `json:"provisioned"`
+
+	// UsedFraction is the fraction of the provisioned storage which is used.
+	UsedFraction float64 `json:"used_fraction"`
+}
+
+// String returns a human-readable description of the warning.
+func (x StorageUsageWarning) String() string {
+	return fmt.Sprintf("%s is %.0f%% full (%s of %s used)", x.Kind, x.UsedFraction*100, x.Used, x.Provisioned)
+}
+
+// UsageWarnings returns a warning for each kind of storage where the used fraction is at or above the threshold. The
+// threshold is a fraction, so 0.9 will warn when 90% of the storage is used.
+func (x DeploymentStorageInfo) UsageWarnings(threshold float64) []StorageUsageWarning {
+	var warnings []StorageUsageWarning
+	add := func(kind StorageKind, size *DeploymentStorageSize) {
+		if size == nil || size.ProvisionedSize <= 0 {
+			return
+		}
+		if f := size.UsedFraction(); f >= threshold {
+			warnings = append(warnings, StorageUsageWarning{
+				Kind:         kind,
+				Used:         size.Used(),
+				Provisioned:  size.Provisioned(),
+				UsedFraction: f,
+			})
+		}
+	}
+	add(StorageKindVolume, x.Volume)
+	add(StorageKindBuildCache, x.BuildCache)
+	return warnings
+}
+
+// VolumeResizeState is used to define the state of a volume resize.
+type VolumeResizeState string
+
+const (
+	// VolumeResizeStatePending is used to define a resize which has not been applied yet.
+	VolumeResizeStatePending VolumeResizeState = "pending"
+
+	// VolumeResizeStateCompleted is used to define a resize which has been applied.
+	VolumeResizeStateCompleted VolumeResizeState = "completed"
+
+	// VolumeResizeStateFailed is used to define a resize which failed.
+	VolumeResizeStateFailed VolumeResizeState = "failed"
+)
+
+// VolumeResize is used to define a resize of a deployment volume.
+type VolumeResize struct {
+	// VolumeID is the ID of the volume being resized.
+	VolumeID string `json:"volume_id"`
+
+	// Size is the size the volume is being resized to.
+	Size Size `json:"size"`
+
+	// State is the state of the resize.
+	State VolumeResizeState `json:"state"`
+
+	// CreatedAt is when the resize was requested.
+	CreatedAt Timestamp `json:"created_at"`
+}
+
+// Pending returns true if the resize has not been applied yet.
+func (x VolumeResize) Pending() bool {
+	return x.State == VolumeResizeStatePending
+}
+
+// VolumeSnapshot is used to define a point in time snapshot of a deployment volume.
+type VolumeSnapshot struct {
+	// ID is the ID of the snapshot.
+	ID string `json:"id"`
+
+	// VolumeID is the ID of the volume the snapshot was taken from.
+	VolumeID string `json:"volume_id"`
+
+	// Size is the size of the volume when the snapshot was taken.
+	Size Size `json:"size"`
+
+	// CreatedAt is when the snapshot was taken.
+	CreatedAt Timestamp `json:"created_at"`
+}
+
+// DeploymentVerdict is used to define the overall health of a deployment.
+type DeploymentVerdict string
+
+const (
+	// DeploymentVerdictReady is used to define a deployment where every container is ready.
+	DeploymentVerdictReady DeploymentVerdict = "ready"
+
+	// DeploymentVerdictDegraded is used to define a deployment where some containers are ready, or the latest rollout
+	// failed its health checks.
+	DeploymentVerdictDegraded DeploymentVerdict = "degraded"
+
+	// DeploymentVerdictDown is used to define a deployment where no containers are ready.
+	DeploymentVerdictDown DeploymentVerdict = "down"
+)
+
+// ContainerHealth is used to define the health of a container within a deployment status report.
+type ContainerHealth struct {
+	// ContainerID is the ID of the container.
+	ContainerID string `json:"container_id"`
+
+	// State is the state of the container.
+	State ContainerState `json:"state"`
+
+	// Ready is true if the container is running and all of its health checks have succeeded.
+	Ready bool `json:"ready"`
+
+	// LastExitCode is the last exit code of the container. It is nil if the container has never exited.
+	LastExitCode *int `json:"last_exit_code"`
+
+	// LastStart is the last time the container was started.
+	LastStart Timestamp `json:"last_start"`
+
+	// HealthChecks is the state of each health check for the container.
+	HealthChecks []*HealthCheckState `json:"health_checks"`
+
+	// Hints are human-readable hints about why the container is not ready or has restarted.
+	Hints []string `json:"hints"`
+}
+
+// DeploymentStatus is used to define a consolidated report of the health of a deployment.
+type DeploymentStatus struct {
+	// DeploymentID is the ID of the deployment.
+	DeploymentID string `json:"deployment_id"`
+
+	// Verdict is the overall health of the deployment.
+	Verdict DeploymentVerdict `json:"verdict"`
+
+	// Containers is the health of each container in the deployment.
+	Containers []*ContainerHealth `json:"containers"`
+
+	// HealthChecks is the health checks configured on the deployment.
+	HealthChecks []*HealthCheck `json:"health_checks"`
+
+	// LatestRollout is the latest rollout of the deployment. Can be nil.
+	LatestRollout *DeploymentRollout `json:"latest_rollout"`
+
+	// Hints are human-readable hints about the deployment as a whole.
+	Hints []string `json:"hints"`
+}
@@ -133,3 +133,153 @@ func TestPresetForm_JSONMarshalling(t *testing.T) {
 		})
 	}
 }
+
+func TestDeploymentStorageSize(t *testing.T) {
+	s := DeploymentStorageSize{ProvisionedSize: 200, UsedSize: 50}
+	assert.Equal(t, Megabytes(200), s.Provisioned())
+	assert.Equal(t, Megabytes(50), s.Used())
+	assert.Equal(t, 0.25, s.UsedFraction())
+	assert.Equal(t, float64(0), DeploymentStorageSize{UsedSize: 50}.UsedFraction())
+}
+
+func TestDeploymentStorageInfo_UsageWarnings(t *testing.T) {
+	info := DeploymentStorageInfo{
+		Volume:     &DeploymentStorageSize{ProvisionedSize: 100, UsedSize: 80},
+		BuildCache: &DeploymentStorageSize{ProvisionedSize: 100, UsedSize: 90},
+	}
+	warnings := info.UsageWarnings(0.9)
+	assert.Equal(t, []StorageUsageWarning{{
+		Kind:         StorageKindBuildCache,
+		Used:         Megabytes(90),
+		Provisioned:  Megabytes(100),
+		UsedFraction: 0.9,
+	}}, warnings)
+	assert.Equal(t, "build_cache is 90% full (90mb of 100mb used)", warnings[0].String())
+	assert.Len(t, info.UsageWarnings(0.5), 2)
+	assert.Nil(t, DeploymentStorageInfo{}.UsageWarnings(0))
+}
+
+func TestVolumeResize_Pending(t *testing.T) {
+	assert.True(t, VolumeResize{State: VolumeResizeStatePending}.Pending())
+	assert.False(t, VolumeResize{State: VolumeResizeStateFailed}.Pending())
+}
+
+func TestGatewayCreationOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+
+		opts    GatewayCreationOptions
+		wantErr string
+	}{
+		{name: "defaults", opts: GatewayCreationOptions{Type: GatewayTypeExternal}},
+		{
+			name: "http",
+			opts: GatewayCreationOptions{Type: GatewayTypeExternal, Protocol: GatewayProtocolHTTP, TargetPort: 8080},
+		},
+		{
+			name: "tcp with external port",
+			opts: GatewayCreationOptions{
+				Type: GatewayTypeExternal, Protocol: GatewayProtocolTCP, TargetPort: 25565, ExternalPort: 25565,
+			},
+		},
+		{
+			name: "udp with assigned port",
+			opts: GatewayCreationOptions{Type: GatewayTypeExternal, Protocol: GatewayProtocolUDP, TargetPort: 7777},
+		},
+		{
+			name: "grpc",
+			opts: GatewayCreationOptions{Type: GatewayTypeExternal, Protocol: GatewayProtocolGRPC, TargetPort: 50051},
+		},
+		{name: "internal", opts: GatewayCreationOptions{Type: GatewayTypeInternal, TargetPort: 5432}},
+		{
+			name: "internal grpc",
+			opts: GatewayCreationOptions{Type: GatewayTypeInternal, Protocol: GatewayProtocolGRPC, TargetPort: 50051},
+		},
+		{
+			name: "internal tcp",
+			opts: GatewayCreationOptions{Type: GatewayTypeInternal, Protocol: GatewayProtocolTCP, TargetPort: 5432},
+		},
+		{
+			name: "internal with external port",
+			opts: GatewayCreationOptions{
+				Type: GatewayTypeInternal, Protocol: GatewayProtocolTCP, TargetPort: 5432, ExternalPort: 5432,
+			},
+			wantErr: "external port must not be set on internal gateways",
+		},
+		{
+			name:    "unknown protocol",
+			opts:    GatewayCreationOptions{Type: GatewayTypeExternal, Protocol: "sctp", TargetPort: 80},
+			wantErr: "unknown gateway protocol: sctp",
+		},
+		{
+			name:    "tcp without target port",
+			opts:    GatewayCreationOptions{Type: GatewayTypeExternal, Protocol: GatewayProtocolTCP},
+			wantErr: "target port must be set on tcp gateways",
+		},
+		{
+			name: "external port on grpc",
+			opts: GatewayCreationOptions{
+				Type: GatewayTypeExternal, Protocol: GatewayProtocolGRPC, TargetPort: 50051, ExternalPort: 50051,
+			},
+			wantErr: "external port can only be set on tcp and udp gateways",
+		},
+		{
+			name:    "external port without protocol",
+			opts:    GatewayCreationOptions{Type: GatewayTypeExternal, ExternalPort: 80},
+			wantErr: "external port can only be set on tcp and udp gateways",
+		},
+		{
+			name:    "target port out of range",
+			opts:    GatewayCreationOptions{Type: GatewayTypeExternal, TargetPort: 70000},
+			wantErr: "target port must be between 1 and 65535",
+		},
+		{
+			name: "external port out of range",
+			opts: GatewayCreationOptions{
+				Type: GatewayTypeExternal, Protocol: GatewayProtocolUDP, TargetPort: 7777, ExternalPort: -1,
+			},
+			wantErr: "external port must be between 1 and 65535",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIgniteGatewayUpdateOpts_Validate(t *testing.T) {
+	assert.NoError(t, IgniteGatewayUpdateOpts{Name: "x"}.Validate())
+	assert.NoError(t, IgniteGatewayUpdateOpts{Protocol: GatewayProtocolGRPC, TargetPort: 50051}.Validate())
+	assert.EqualError(t, IgniteGatewayUpdateOpts{Protocol: "sctp"}.Validate(), "unknown gateway protocol: sctp")
+	assert.EqualError(t, IgniteGatewayUpdateOpts{TargetPort: -1}.Validate(), "target port must be between 1 and 65535")
+}
+
+func TestGateway_ExternalAddress(t *testing.T) {
+	port := 25565
+	assert.Equal(t, "game.hop.sh:25565", Gateway{HopshDomain: "game.hop.sh", ExternalPort: &port}.ExternalAddress())
+	assert.Equal(t, "", Gateway{HopshDomain: "game.hop.sh"}.ExternalAddress())
+	assert.Equal(t, "", Gateway{ExternalPort: &port}.ExternalAddress())
+}
+
+func TestHealthCheckCreateOpts_WithDefaults(t *testing.T) {
+	assert.Equal(t, HealthCheckCreateOpts{
+		Protocol:     HealthCheckProtocolHTTP,
+		Path:         "/",
+		Port:         8080,
+		InitialDelay: SecondsFromInt(5),
+		Interval:     SecondsFromInt(60),
+		Timeout:      MillisecondsFromInt(50),
+		MaxRetries:   3,
+	}, HealthCheckCreateOpts{}.WithDefaults())
+
+	opts := HealthCheckCreateOpts{Path: "/health", Port: 3000, MaxRetries: 1}.WithDefaults()
+	assert.Equal(t, "/health", opts.Path)
+	assert.Equal(t, 3000, opts.Port)
+	assert.Equal(t, 1, opts.MaxRetries)
+}
@@ -94,6 +94,9 @@ func (x LeapUnavailableEvent) Error() string {
 type LeapMessageEvent struct {
 	LeapDispatchEventDetails `json:",inline"`
 
+	// ID is the ID of the message. This can be used to find where to replay messages from when subscribing.
+	ID string `json:"id"`
+
 	// Data is the user provided event data for the message.
 	Data map[string]any `json:"d"`
 
@@ -162,6 +165,43 @@ type LeapPipeRoomAvailableEvent struct {
 // LeapPipeRoomUpdateEvent contains the same data as LeapPipeRoomAvailableEvent.
 type LeapPipeRoomUpdateEvent LeapPipeRoomAvailableEvent
 
+// LeapContainerUpdateEvent is used to define the event when a container in the project is created, changes state, or
+// is deleted. This is only sent to project scoped connections.
+type LeapContainerUpdateEvent struct {
+	LeapDispatchEventDetails `json:",inline"`
+
+	// Container is the container after the update.
+	Container *Container `json:"container"`
+}
+
+// LeapRolloutUpdateEvent is used to define the event when a rollout in the project is created or progresses. This is
+// only sent to project scoped connections.
+type LeapRolloutUpdateEvent struct {
+	LeapDispatchEventDetails `json:",inline"`
+
+	// Rollout is the rollout after the update.
+	Rollout *DeploymentRollout `json:"rollout"`
+}
+
+// LeapBuildUpdateEvent is used to define the event when a build in the project is created or changes state. This is
+// only sent to project scoped connections.
+type LeapBuildUpdateEvent struct {
+	LeapDispatchEventDetails `json:",inline"`
+
+	// Build is the build after the update.
+	Build *Build `json:"build"`
+}
+
+// LeapHealthCheckUpdateEvent is used to define the event when the state of a health check on a container in the
+// project changes. This is only sent to project scoped connections.
+type LeapHealthCheckUpdateEvent struct {
+	LeapDispatchEventDetails `json:",inline"`
+
+	// State is the health check state after the update.
+	State *HealthCheckState `json:"state"`
+}
+
 // LeapChannelEvent is an any type that can be one of LeapUnavailableEvent, LeapAvailableEvent, LeapChannelStateUpdateEvent,
-// LeapPipeRoomAvailableEvent, and LeapPipeRoomUpdateEvent.
+// LeapPipeRoomAvailableEvent, LeapPipeRoomUpdateEvent, LeapContainerUpdateEvent, LeapRolloutUpdateEvent,
+// LeapBuildUpdateEvent, and LeapHealthCheckUpdateEvent.
 type LeapChannelEvent any
@@ -1,13 +1,32 @@
 package types
 
+import (
+	"errors"
+	"net/url"
+)
+
 // IngestProtocol is the type used for supported Pipe ingest protocols.
 type IngestProtocol string
 
 const (
 	// IngestProtocolRTMP is used to define the RTMP ingest protocol.
 	IngestProtocolRTMP IngestProtocol = "rtmp"
+
+	// IngestProtocolSRT is used to define the SRT ingest protocol.
+	IngestProtocolSRT IngestProtocol = "srt"
+
+	// IngestProtocolWHIP is used to define the WHIP (WebRTC-HTTP ingestion protocol) ingest protocol.
+	IngestProtocolWHIP IngestProtocol = "whip"
 )
 
+// Defines the delivery protocols which can be used with each ingest protocol. WHIP streams are not transcoded, so
+// they can only be delivered over WebRTC.
+var ingestDeliveryProtocols = map[IngestProtocol][]DeliveryProtocol{
+	IngestProtocolRTMP: {DeliveryProtocolHLS, DeliveryProtocolWebRTC},
+	IngestProtocolSRT:  {DeliveryProtocolHLS, DeliveryProtocolWebRTC},
+	IngestProtocolWHIP: {DeliveryProtocolWebRTC},
+}
+
 // DeliveryProtocol is the type used for supported Pipe delivery protocols.
 type DeliveryProtocol string
 
@@ -19,6 +38,13 @@ const (
 	DeliveryProtocolWebRTC DeliveryProtocol = "webrtc"
 )
 
+func (x DeliveryProtocol) validate() error {
+	if x != DeliveryProtocolHLS && x != DeliveryProtocolWebRTC {
+		return errors.New("unsupported delivery protocol: " + string(x))
+	}
+	return nil
+}
+
 // RoomState is used to define the state of a room.
 type RoomState string
 
@@ -55,13 +81,163 @@ type Room struct {
 
 	// State is the current state of the room.
 	State RoomState `json:"state"`
+
+	// StreamKey is the key used to stream to the room. This is only returned to project members.
+	StreamKey string `json:"stream_key"`
+
+	// HLSConfig is the configuration for HLS delivery. This is nil if HLS is not a delivery protocol.
+	HLSConfig *HLSConfig `json:"llhls_config"`
+
+	// Recording is the recording configuration of the room. This is nil if the room is not recorded.
+	Recording *RoomRecordingConfig `json:"recording"`
+}
+
+const (
+	// PipeIngestDomain is the domain which streams are sent to. The ingest region of the room is prepended to this.
+	PipeIngestDomain = "ingest.hop.io"
+
+	// PipePlaybackDomain is the domain which streams are played from. The ingest region of the room is prepended to
+	// this.
+	PipePlaybackDomain = "playback.hop.io"
+)
+
+// IngestURL returns the URL which should be used to stream to the room. This includes the stream key, so it should
+// be kept secret.
+func (r Room) IngestURL() (string, error) {
+	if r.IngestRegion == "" {
+		return "", errors.New("room does not have an ingest region")
+	}
+	if r.StreamKey == "" {
+		return "", errors.New("room does not have a stream key")
+	}
+	host := string(r.IngestRegion) + "." + PipeIngestDomain
+	switch r.IngestProtocol {
+	case IngestProtocolRTMP:
+		return "rtmp://" + host + "/live/" + url.PathEscape(r.StreamKey), nil
+	case IngestProtocolSRT:
+		return "srt://" + host + ":9000?streamid=" + url.QueryEscape(r.StreamKey), nil
+	case IngestProtocolWHIP:
+		return "https://" + host + "/whip/" + url.PathEscape(r.StreamKey), nil
+	default:
+		return "", errors.New("unsupported ingest protocol: " + string(r.IngestProtocol))
+	}
+}
+
+// PlaybackURL returns the URL which clients can use to play the room with the delivery protocol specified. The join
+// token is included in the URL. Returns an error if the room does not support the delivery protocol.
+func (r Room) PlaybackURL(protocol DeliveryProtocol) (string, error) {
+	supported := false
+	for _, v := range r.DeliveryProtocols {
+		if v == protocol {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return "", errors.New("room does not support the delivery protocol " + string(protocol))
+	}
+	if r.IngestRegion == "" {
+		return "", errors.New("room does not have an ingest region")
+	}
+
+	base := "https://" + string(r.IngestRegion) + "." + PipePlaybackDomain + "/"
+	query := ""
+	if r.JoinToken != "" {
+		query = "?token=" + url.QueryEscape(r.JoinToken)
+	}
+	switch protocol {
+	case DeliveryProtocolHLS:
+		return base + "hls/" + url.PathEscape(r.ID) + "/index.m3u8" + query, nil
+	case DeliveryProtocolWebRTC:
+		return base + "webrtc/" + url.PathEscape(r.ID) + query, nil
+	default:
+		return "", errors.New("unsupported delivery protocol: " + string(protocol))
+	}
+}
+
+// BitratePreset is used to define the maximum bitrate which a room is played out at.
+type BitratePreset string
+
+const (
+	// BitratePreset1080p is used to define a maximum playout bitrate suitable for 1080p.
+	BitratePreset1080p BitratePreset = "p1080"
+
+	// BitratePreset720p is used to define a maximum playout bitrate suitable for 720p.
+	BitratePreset720p BitratePreset = "p720"
+
+	// BitratePreset480p is used to define a maximum playout bitrate suitable for 480p.
+	BitratePreset480p BitratePreset = "p480"
+
+	// BitratePreset360p is used to define a maximum playout bitrate suitable for 360p.
+	BitratePreset360p BitratePreset = "p360"
+)
+
+func (x BitratePreset) validate() error {
+	switch x {
+	case BitratePreset1080p, BitratePreset720p, BitratePreset480p, BitratePreset360p:
+		return nil
+	default:
+		return errors.New("invalid max playout bitrate preset: " + string(x))
+	}
 }
 
 // HLSConfig is used to define the HLS configuration for a room.
 type HLSConfig struct {
-	WCLDelay                int    `json:"wcl_delay"`
-	ArtificialDelay         int    `json:"artificial_delay"`
-	MaxPlayoutBitratePreset string `json:"max_playout_bitrate_preset"`
+	// WCLDelay is the wall clock delay in seconds.
+	WCLDelay int `json:"wcl_delay"`
+
+	// ArtificialDelay is the delay in seconds which is added to the stream.
+	ArtificialDelay int `json:"artificial_delay"`
+
+	// MaxPlayoutBitratePreset is the maximum bitrate which the room is played out at. If this is blank, the bitrate
+	// is not limited.
+	MaxPlayoutBitratePreset BitratePreset `json:"max_playout_bitrate_preset"`
+}
+
+// Validate is used to check the delays are not negative and the bitrate preset is valid.
+func (x HLSConfig) Validate() error {
+	if x.WCLDelay < 0 {
+		return errors.New("wcl delay must not be negative")
+	}
+	if x.ArtificialDelay < 0 {
+		return errors.New("artificial delay must not be negative")
+	}
+	if x.MaxPlayoutBitratePreset != "" {
+		return x.MaxPlayoutBitratePreset.validate()
+	}
+	return nil
+}
+
+// RoomRecordingConfig is used to define the recording configuration for a room. Recordings are made from the HLS
+// segments, so HLS must be a delivery protocol of the room.
+type RoomRecordingConfig struct {
+	// DVRWindow is how far back viewers can seek in the live stream. If this is 0, viewers cannot seek.
+	DVRWindow Seconds `json:"dvr_window,omitempty"`
+
+	// SaveToStorage defines whether the stream is saved to storage once it ends.
+	SaveToStorage bool `json:"save_to_storage"`
+
+	// StoragePath is the path in the project storage which recordings are saved to. If this is blank, the default
+	// path is used.
+	StoragePath string `json:"storage_path,omitempty"`
+
+	// Retention is how long recordings are kept for. If this is 0, recordings are kept until they are deleted.
+	Retention Seconds `json:"retention,omitempty"`
+}
+
+// Validate is used to check the durations are not negative and that storage options are only set when the stream
+// is saved to storage.
+func (x RoomRecordingConfig) Validate() error {
+	if x.DVRWindow < 0 {
+		return errors.New("dvr window must not be negative")
+	}
+	if x.Retention < 0 {
+		return errors.New("retention must not be negative")
+	}
+	if !x.SaveToStorage && (x.StoragePath != "" || x.Retention != 0) {
+		return errors.New("storage path and retention can only be set when saving to storage")
+	}
+	return nil
 }
 
 // RoomCreationOptions is used to define the options for creating a room.
@@ -81,6 +257,97 @@ type RoomCreationOptions struct {
 	// IngestProtocol is the protocol you can stream with.
 	IngestProtocol IngestProtocol `json:"ingest_protocol"`
 
-	// HLSConfig is the configuration for HLS delivery. This can be nil.
+	// HLSConfig is the configuration for HLS delivery. This should be blank if HLS is not a delivery protocol.
 	HLSConfig HLSConfig `json:"llhls_config"`
+
+	// Recording is the recording configuration of the room. If this is nil, the room is not recorded.
+	Recording *RoomRecordingConfig `json:"recording,omitempty"`
+}
+
+// Validate is used to check the ingest and delivery protocols are a valid combination, and that the HLS and recording
+// configuration is consistent with them. A blank ingest protocol is treated as RTMP. This is called when the room is
+// created.
+func (x RoomCreationOptions) Validate() error {
+	ingest := x.IngestProtocol
+	if ingest == "" {
+		ingest = IngestProtocolRTMP
+	}
+	supported, ok := ingestDeliveryProtocols[ingest]
+	if !ok {
+		return errors.New("unsupported ingest protocol: " + string(ingest))
+	}
+
+	hls := false
+	for _, v := range x.DeliveryProtocols {
+		if err := v.validate(); err != nil {
+			return err
+		}
+		found := false
+		for _, s := range supported {
+			if s == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New(string(v) + " delivery is not supported with " + string(ingest) + " ingest")
+		}
+		if v == DeliveryProtocolHLS {
+			hls = true
+		}
+	}
+
+	if !hls && x.HLSConfig != (HLSConfig{}) {
+		return errors.New("hls config can only be set when hls is a delivery protocol")
+	}
+	if err := x.HLSConfig.Validate(); err != nil {
+		return err
+	}
+
+	if x.Recording != nil {
+		if !hls {
+			return errors.New("recording requires hls to be a delivery protocol")
+		}
+		if err := x.Recording.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RoomUpdateOptions is used to define the options for updating a room. Any blank fields are not changed.
+type RoomUpdateOptions struct {
+	// Name is the name of the room.
+	Name string `json:"name,omitempty"`
+
+	// DeliveryProtocols are the protocols that are supported by this room to the client.
+	DeliveryProtocols []DeliveryProtocol `json:"delivery_protocols,omitempty"`
+
+	// HLSConfig is the configuration for HLS delivery.
+	HLSConfig *HLSConfig `json:"llhls_config,omitempty"`
+
+	// Recording is the recording configuration of the room.
+	Recording *RoomRecordingConfig `json:"recording,omitempty"`
+}
+
+// Validate is used to check the delivery protocols are supported and the HLS and recording configuration is valid.
+// Whether the delivery protocols are supported by the ingest protocol of the room is checked by the API. This is
+// called when the room is updated.
+func (x RoomUpdateOptions) Validate() error {
+	for _, v := range x.DeliveryProtocols {
+		if err := v.validate(); err != nil {
+			return err
+		}
+	}
+	if x.HLSConfig != nil {
+		if err := x.HLSConfig.Validate(); err != nil {
+			return err
+		}
+	}
+	if x.Recording != nil {
+		if err := x.Recording.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,211 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_IngestURL(t *testing.T) {
+	r := Room{IngestProtocol: IngestProtocolRTMP, IngestRegion: RegionUSEast1, StreamKey: "key/1"}
+	u, err := r.IngestURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "rtmp://us-east-1.ingest.hop.io/live/key%2F1", u)
+
+	r.IngestProtocol = IngestProtocolSRT
+	u, err = r.IngestURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "srt://us-east-1.ingest.hop.io:9000?streamid=key%2F1", u)
+
+	r.IngestProtocol = IngestProtocolWHIP
+	u, err = r.IngestURL()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://us-east-1.ingest.hop.io/whip/key%2F1", u)
+
+	_, err = Room{IngestProtocol: IngestProtocolRTMP, IngestRegion: RegionUSEast1}.IngestURL()
+	assert.EqualError(t, err, "room does not have a stream key")
+	_, err = Room{IngestProtocol: "webrtc", IngestRegion: RegionUSEast1, StreamKey: "key"}.IngestURL()
+	assert.EqualError(t, err, "unsupported ingest protocol: webrtc")
+}
+
+func TestRoom_PlaybackURL(t *testing.T) {
+	r := Room{
+		ID:                "pipe_room_1",
+		IngestRegion:      RegionUSEast1,
+		DeliveryProtocols: []DeliveryProtocol{DeliveryProtocolHLS, DeliveryProtocolWebRTC},
+		JoinToken:         "token",
+	}
+	tests := []struct {
+		name string
+
+		room     Room
+		protocol DeliveryProtocol
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "hls",
+			room:     r,
+			protocol: DeliveryProtocolHLS,
+			want:     "https://us-east-1.playback.hop.io/hls/pipe_room_1/index.m3u8?token=token",
+		},
+		{
+			name:     "webrtc",
+			room:     r,
+			protocol: DeliveryProtocolWebRTC,
+			want:     "https://us-east-1.playback.hop.io/webrtc/pipe_room_1?token=token",
+		},
+		{
+			name:     "unsupported",
+			room:     Room{ID: "pipe_room_1", IngestRegion: RegionUSEast1},
+			protocol: DeliveryProtocolHLS,
+			wantErr:  "room does not support the delivery protocol hls",
+		},
+		{
+			name:     "no region",
+			room:     Room{ID: "pipe_room_1", DeliveryProtocols: []DeliveryProtocol{DeliveryProtocolHLS}},
+			protocol: DeliveryProtocolHLS,
+			wantErr:  "room does not have an ingest region",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := tt.room.PlaybackURL(tt.protocol)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, u)
+		})
+	}
+}
+
+func TestRoomCreationOptions_Validate(t *testing.T) {
+	hls := []DeliveryProtocol{DeliveryProtocolHLS}
+	tests := []struct {
+		name string
+
+		opts    RoomCreationOptions
+		wantErr string
+	}{
+		{name: "defaults", opts: RoomCreationOptions{Name: "test"}},
+		{
+			name: "rtmp with hls and webrtc",
+			opts: RoomCreationOptions{
+				IngestProtocol:    IngestProtocolRTMP,
+				DeliveryProtocols: []DeliveryProtocol{DeliveryProtocolHLS, DeliveryProtocolWebRTC},
+				HLSConfig:         HLSConfig{WCLDelay: 6, ArtificialDelay: 1, MaxPlayoutBitratePreset: BitratePreset720p},
+				Recording:         &RoomRecordingConfig{DVRWindow: Seconds(time.Hour), SaveToStorage: true, StoragePath: "/vods"},
+			},
+		},
+		{
+			name: "whip with webrtc",
+			opts: RoomCreationOptions{IngestProtocol: IngestProtocolWHIP, DeliveryProtocols: []DeliveryProtocol{DeliveryProtocolWebRTC}},
+		},
+		{
+			name:    "unknown ingest",
+			opts:    RoomCreationOptions{IngestProtocol: "hls"},
+			wantErr: "unsupported ingest protocol: hls",
+		},
+		{
+			name:    "unknown delivery",
+			opts:    RoomCreationOptions{DeliveryProtocols: []DeliveryProtocol{"dash"}},
+			wantErr: "unsupported delivery protocol: dash",
+		},
+		{
+			name:    "whip with hls",
+			opts:    RoomCreationOptions{IngestProtocol: IngestProtocolWHIP, DeliveryProtocols: hls},
+			wantErr: "hls delivery is not supported with whip ingest",
+		},
+		{
+			name: "hls config without hls",
+			opts: RoomCreationOptions{
+				DeliveryProtocols: []DeliveryProtocol{DeliveryProtocolWebRTC},
+				HLSConfig:         HLSConfig{WCLDelay: 6},
+			},
+			wantErr: "hls config can only be set when hls is a delivery protocol",
+		},
+		{
+			name:    "negative delay",
+			opts:    RoomCreationOptions{DeliveryProtocols: hls, HLSConfig: HLSConfig{ArtificialDelay: -1}},
+			wantErr: "artificial delay must not be negative",
+		},
+		{
+			name:    "invalid preset",
+			opts:    RoomCreationOptions{DeliveryProtocols: hls, HLSConfig: HLSConfig{MaxPlayoutBitratePreset: "4k"}},
+			wantErr: "invalid max playout bitrate preset: 4k",
+		},
+		{
+			name:    "recording without hls",
+			opts:    RoomCreationOptions{Recording: &RoomRecordingConfig{SaveToStorage: true}},
+			wantErr: "recording requires hls to be a delivery protocol",
+		},
+		{
+			name:    "storage path without saving",
+			opts:    RoomCreationOptions{DeliveryProtocols: hls, Recording: &RoomRecordingConfig{StoragePath: "/vods"}},
+			wantErr: "storage path and retention can only be set when saving to storage",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRoomUpdateOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+
+		opts    RoomUpdateOptions
+		wantErr string
+	}{
+		{name: "blank", opts: RoomUpdateOptions{}},
+		{
+			name: "valid",
+			opts: RoomUpdateOptions{
+				Name:              "test",
+				DeliveryProtocols: []DeliveryProtocol{DeliveryProtocolHLS, DeliveryProtocolWebRTC},
+				HLSConfig:         &HLSConfig{WCLDelay: 6, MaxPlayoutBitratePreset: BitratePreset720p},
+				Recording:         &RoomRecordingConfig{SaveToStorage: true, Retention: Seconds(time.Hour)},
+			},
+		},
+		{
+			name:    "unknown delivery",
+			opts:    RoomUpdateOptions{DeliveryProtocols: []DeliveryProtocol{"dash"}},
+			wantErr: "unsupported delivery protocol: dash",
+		},
+		{
+			name:    "negative delay",
+			opts:    RoomUpdateOptions{HLSConfig: &HLSConfig{WCLDelay: -1}},
+			wantErr: "wcl delay must not be negative",
+		},
+		{
+			name:    "invalid preset",
+			opts:    RoomUpdateOptions{HLSConfig: &HLSConfig{MaxPlayoutBitratePreset: "4k"}},
+			wantErr: "invalid max playout bitrate preset: 4k",
+		},
+		{
+			name:    "retention without saving",
+			opts:    RoomUpdateOptions{Recording: &RoomRecordingConfig{Retention: Seconds(time.Hour)}},
+			wantErr: "storage path and retention can only be set when saving to storage",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
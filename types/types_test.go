@@ -18,6 +18,8 @@ var types = []reflect.Type{
 	reflect.TypeOf(ChannelPartial{}),
 	reflect.TypeOf(Stats{}),
 	reflect.TypeOf(ChannelToken{}),
+	reflect.TypeOf(ChannelTokenCreationOptions{}),
+	reflect.TypeOf(ChannelMessage{}),
 
 	// errors.go
 	reflect.TypeOf(BadRequest{}),
@@ -31,6 +33,8 @@ var types = []reflect.Type{
 	reflect.TypeOf(VolumeDefinition{}),
 	reflect.TypeOf(RestartPolicy("")),
 	reflect.TypeOf(Domain{}),
+	reflect.TypeOf(DNSRecordType("")),
+	reflect.TypeOf(DNSRecord{}),
 	reflect.TypeOf(Gateway{}),
 	reflect.TypeOf(ContainerStrategy("")),
 	reflect.TypeOf(RuntimeType("")),
@@ -71,14 +75,25 @@ var types = []reflect.Type{
 	reflect.TypeOf(DeploymentStorageSize{}),
 	reflect.TypeOf(DeploymentStorageInfo{}),
 	reflect.TypeOf(DeploymentMetadata{}),
+	reflect.TypeOf(VolumeResizeState("")),
+	reflect.TypeOf(VolumeResize{}),
+	reflect.TypeOf(VolumeSnapshot{}),
+	reflect.TypeOf(StorageKind("")),
+	reflect.TypeOf(StorageUsageWarning{}),
+	reflect.TypeOf(DeploymentVerdict("")),
+	reflect.TypeOf(ContainerHealth{}),
+	reflect.TypeOf(DeploymentStatus{}),
 
 	// pipe.go
 	reflect.TypeOf(IngestProtocol("")),
 	reflect.TypeOf(DeliveryProtocol("")),
 	reflect.TypeOf(RoomState("")),
+	reflect.TypeOf(BitratePreset("")),
 	reflect.TypeOf(Room{}),
 	reflect.TypeOf(HLSConfig{}),
 	reflect.TypeOf(RoomCreationOptions{}),
+	reflect.TypeOf(RoomUpdateOptions{}),
+	reflect.TypeOf(RoomRecordingConfig{}),
 
 	// projects.go
 	reflect.TypeOf(ProjectTier("")),
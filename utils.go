@@ -45,6 +45,13 @@ func handleErrors(res *http.Response) error {
 			Message: r.Error.Message,
 		}
 	}
+	if res.StatusCode == 412 {
+		// The If-Match header did not match the current version.
+		return types.PreconditionFailed{
+			Code:    r.Error.Code,
+			Message: r.Error.Message,
+		}
+	}
 	if res.StatusCode >= 500 {
 		// Infer that this is a internal server error.
 		return types.ServerError(r.Error.Message)
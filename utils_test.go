@@ -68,6 +68,13 @@ func Test_handleErrors(t *testing.T) {
 			expectsErr:     "oof",
 			expectsErrType: types.NotFound{},
 		},
+		{
+			name:           "precondition failed",
+			status:         412,
+			body:           []byte(`{"success":false,"error":{"code":"precondition_failed","message":"oof"}}`),
+			expectsErr:     "oof",
+			expectsErrType: types.PreconditionFailed{},
+		},
 		{
 			name:           "server error",
 			status:         500,
@@ -0,0 +1,318 @@
+// Package webhooks is used to receive Hop webhooks. The handler verifies the signature of each request, decodes the
+// event into the structs from the types package, and passes it to the handler registered for that kind of event.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.hop.io/sdk"
+	"go.hop.io/sdk/types"
+)
+
+const (
+	// SignatureHeader is the header which contains the hex encoded HMAC-SHA256 of "<timestamp>.<body>", using the
+	// webhook secret as the key.
+	SignatureHeader = "X-Hop-Signature"
+
+	// TimestampHeader is the header which contains the unix timestamp in seconds of when the request was signed.
+	TimestampHeader = "X-Hop-Timestamp"
+
+	// DefaultTolerance is how far the timestamp of a request can be from the current time if Config.Tolerance is 0.
+	DefaultTolerance = 5 * time.Minute
+
+	// The maximum size of a request body.
+	maxBodySize = 1 << 20
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of a request is missing or does not match.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrTimestampOutOfRange is returned when the timestamp of a request is outside of the tolerance. This prevents
+	// old requests from being replayed.
+	ErrTimestampOutOfRange = errors.New("webhook timestamp is outside of the tolerance")
+)
+
+// EventName is used to define the name of a webhook event.
+type EventName string
+
+const (
+	// EventDeploymentCreated is sent when a deployment is created. The data is a types.Deployment.
+	EventDeploymentCreated EventName = "ignite.deployment.created"
+
+	// EventDeploymentUpdated is sent when a deployment is updated. The data is a types.Deployment.
+	EventDeploymentUpdated EventName = "ignite.deployment.updated"
+
+	// EventDeploymentDeleted is sent when a deployment is deleted. The data is a types.Deployment.
+	EventDeploymentDeleted EventName = "ignite.deployment.deleted"
+
+	// EventBuildCreated is sent when a build is started. The data is a types.Build.
+	EventBuildCreated EventName = "ignite.deployment.build.created"
+
+	// EventBuildUpdated is sent when the state of a build changes. The data is a types.Build.
+	EventBuildUpdated EventName = "ignite.deployment.build.updated"
+
+	// EventRolloutCreated is sent when a rollout is started. The data is a types.DeploymentRollout.
+	EventRolloutCreated EventName = "ignite.deployment.rollout.created"
+
+	// EventRolloutUpdated is sent when the state of a rollout changes. The data is a types.DeploymentRollout.
+	EventRolloutUpdated EventName = "ignite.deployment.rollout.updated"
+
+	// EventContainerCreated is sent when a container is created. The data is a types.Container.
+	EventContainerCreated EventName = "ignite.container.created"
+
+	// EventContainerUpdated is sent when the state of a container changes. The data is a types.Container.
+	EventContainerUpdated EventName = "ignite.container.updated"
+
+	// EventContainerDeleted is sent when a container is deleted. The data is a types.Container.
+	EventContainerDeleted EventName = "ignite.container.deleted"
+
+	// EventChannelCreated is sent when a channel is created. The data is a types.Channel.
+	EventChannelCreated EventName = "channel.created"
+
+	// EventChannelUpdated is sent when a channel is updated. The data is a types.Channel.
+	EventChannelUpdated EventName = "channel.updated"
+
+	// EventChannelDeleted is sent when a channel is deleted. The data is a types.Channel.
+	EventChannelDeleted EventName = "channel.deleted"
+)
+
+// Event is used to define the body of a webhook request.
+type Event struct {
+	// ID is the ID of the event.
+	ID string `json:"id"`
+
+	// WebhookID is the ID of the webhook which sent the event.
+	WebhookID string `json:"webhook_id"`
+
+	// ProjectID is the ID of the project the event happened in.
+	ProjectID string `json:"project_id"`
+
+	// Event is the name of the event.
+	Event EventName `json:"event"`
+
+	// OccurredAt is when the event happened.
+	OccurredAt types.Timestamp `json:"occurred_at"`
+
+	// Data is the raw data of the event. The typed handlers decode this for you.
+	Data json.RawMessage `json:"data"`
+}
+
+// Config is used to define the configuration for a Handler.
+type Config struct {
+	// Secret is the secret of the webhook which is used to verify the signatures. If this is blank, every request is
+	// rejected.
+	Secret string
+
+	// Tolerance is how far the timestamp of a request can be from the current time. If this is 0, it will default to
+	// DefaultTolerance.
+	Tolerance time.Duration
+
+	// Clock is the clock used to check the timestamps. If this is nil, the system clock is used.
+	Clock hop.Clock
+}
+
+// Handler is a http.Handler which receives Hop webhooks. Please use NewHandler to create this, and then register the
+// handlers for the events you want to receive. Events without a handler are acknowledged and ignored. If a handler
+// returns an error, the request fails with a 500 so that Hop will retry it. If the data of the event cannot be decoded,
+// the request fails with a 400 since a retry would fail in the same way.
+type Handler struct {
+	cfg Config
+
+	deployment func(context.Context, *Event, *types.Deployment) error
+	build      func(context.Context, *Event, *types.Build) error
+	rollout    func(context.Context, *Event, *types.DeploymentRollout) error
+	container  func(context.Context, *Event, *types.Container) error
+	channel    func(context.Context, *Event, *types.Channel) error
+	fallback   func(context.Context, *Event) error
+}
+
+// NewHandler is used to create a webhook handler with the configuration specified.
+func NewHandler(cfg Config) *Handler {
+	if cfg.Tolerance == 0 {
+		cfg.Tolerance = DefaultTolerance
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = hop.SystemClock{}
+	}
+	return &Handler{cfg: cfg}
+}
+
+// OnDeployment is used to set the handler for deployment events.
+func (h *Handler) OnDeployment(fn func(ctx context.Context, e *Event, d *types.Deployment) error) {
+	h.deployment = fn
+}
+
+// OnBuild is used to set the handler for build events.
+func (h *Handler) OnBuild(fn func(ctx context.Context, e *Event, b *types.Build) error) { h.build = fn }
+
+// OnRollout is used to set the handler for rollout events.
+func (h *Handler) OnRollout(fn func(ctx context.Context, e *Event, r *types.DeploymentRollout) error) {
+	h.rollout = fn
+}
+
+// OnContainer is used to set the handler for container events.
+func (h *Handler) OnContainer(fn func(ctx context.Context, e *Event, c *types.Container) error) {
+	h.container = fn
+}
+
+// OnChannel is used to set the handler for channel events.
+func (h *Handler) OnChannel(fn func(ctx context.Context, e *Event, c *types.Channel) error) {
+	h.channel = fn
+}
+
+// OnUnknown is used to set the handler for events which are not one of the events above.
+func (h *Handler) OnUnknown(fn func(ctx context.Context, e *Event) error) { h.fallback = fn }
+
+// Sign is used to get the signature of a body which was signed at the time specified.
+func Sign(secret string, t time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(strconv.FormatInt(t.Unix(), 10) + "."))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify is used to check the timestamp and signature headers of a request against the body. The timestamp must be
+// within the tolerance of now. If the secret is blank, ErrInvalidSignature is always returned since anyone could sign
+// a request with a blank key.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	t := time.Unix(unix, 0)
+	if t.Before(now.Add(-tolerance)) || t.After(now.Add(tolerance)) {
+		return ErrTimestampOutOfRange
+	}
+
+	signature, err := hex.DecodeString(strings.TrimSpace(header.Get(SignatureHeader)))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(Sign(secret, t, body))
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Defines an error decoding the data of an event. This is kept separate from handler errors since retrying the event
+// will not help.
+type decodeError struct {
+	err error
+}
+
+func (e decodeError) Error() string { return "unable to decode event data: " + e.err.Error() }
+
+func (e decodeError) Unwrap() error { return e.err }
+
+// Decodes the data of the event into a new T and calls the handler.
+func dispatch[T any](ctx context.Context, e *Event, fn func(context.Context, *Event, *T) error) error {
+	var v T
+	if err := json.Unmarshal(e.Data, &v); err != nil {
+		return decodeError{err: err}
+	}
+	return fn(ctx, e, &v)
+}
+
+// Returns the function to handle the event. Returns nil if there is no handler for the event.
+func (h *Handler) handlerFor(e *Event) func(context.Context) error {
+	switch {
+	case strings.HasPrefix(string(e.Event), "ignite.deployment.build.") && h.build != nil:
+		return func(ctx context.Context) error { return dispatch(ctx, e, h.build) }
+	case strings.HasPrefix(string(e.Event), "ignite.deployment.rollout.") && h.rollout != nil:
+		return func(ctx context.Context) error { return dispatch(ctx, e, h.rollout) }
+	case (e.Event == EventDeploymentCreated || e.Event == EventDeploymentUpdated || e.Event == EventDeploymentDeleted) &&
+		h.deployment != nil:
+		return func(ctx context.Context) error { return dispatch(ctx, e, h.deployment) }
+	case strings.HasPrefix(string(e.Event), "ignite.container.") && h.container != nil:
+		return func(ctx context.Context) error { return dispatch(ctx, e, h.container) }
+	case strings.HasPrefix(string(e.Event), "channel.") && h.channel != nil:
+		return func(ctx context.Context) error { return dispatch(ctx, e, h.channel) }
+	case h.fallback != nil:
+		return func(ctx context.Context) error { return h.fallback(ctx, e) }
+	}
+	return nil
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		if len(body) == maxBodySize {
+			// The reader stopped at the limit, so the body is too large.
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, "unable to read body", http.StatusBadRequest)
+		}
+		return
+	}
+	if err = Verify(h.cfg.Secret, r.Header, body, h.cfg.Tolerance, h.cfg.Clock.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var e Event
+	if err = json.Unmarshal(body, &e); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+	if fn := h.handlerFor(&e); fn != nil {
+		if err = fn(r.Context()); err != nil {
+			if errors.As(err, &decodeError{}) {
+				// The event will never decode, so a retry would fail in the same way.
+				http.Error(w, "invalid event data", http.StatusBadRequest)
+			} else {
+				http.Error(w, "handler failed", http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// NewTestRequest is used to create a signed webhook request for the event specified, which is useful for testing
+// handlers with synthetic events. The request is signed at the time specified.
+func NewTestRequest(secret string, eventName EventName, data any, t time.Time) (*http.Request, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(Event{
+		ID:         "event_test",
+		Event:      eventName,
+		OccurredAt: types.TimestampFromTime(t),
+		Data:       b,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	//nolint:noctx // This request is sent to a handler directly.
+	r, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(TimestampHeader, strconv.FormatInt(t.Unix(), 10))
+	r.Header.Set(SignatureHeader, Sign(secret, t, body))
+	return r, nil
+}
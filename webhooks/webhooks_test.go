@@ -0,0 +1,234 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.hop.io/sdk/types"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func (fixedClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+var testTime = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"channel.created"}`)
+	signed := func(secret string, ts time.Time) http.Header {
+		h := http.Header{}
+		h.Set(TimestampHeader, strconv.FormatInt(ts.Unix(), 10))
+		h.Set(SignatureHeader, Sign(secret, ts, body))
+		return h
+	}
+
+	tests := []struct {
+		name string
+
+		header  http.Header
+		now     time.Time
+		wantErr error
+	}{
+		{name: "valid", header: signed("secret", testTime), now: testTime},
+		{name: "within tolerance", header: signed("secret", testTime), now: testTime.Add(4 * time.Minute)},
+		{name: "wrong secret", header: signed("other", testTime), now: testTime, wantErr: ErrInvalidSignature},
+		{
+			name:    "too old",
+			header:  signed("secret", testTime),
+			now:     testTime.Add(6 * time.Minute),
+			wantErr: ErrTimestampOutOfRange,
+		},
+		{
+			name:    "too new",
+			header:  signed("secret", testTime),
+			now:     testTime.Add(-6 * time.Minute),
+			wantErr: ErrTimestampOutOfRange,
+		},
+		{name: "missing headers", header: http.Header{}, now: testTime, wantErr: ErrInvalidSignature},
+		{
+			name:    "invalid signature",
+			header:  http.Header{TimestampHeader: {"1640995200"}, SignatureHeader: {"zz"}},
+			now:     testTime,
+			wantErr: ErrInvalidSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, Verify("secret", tt.header, body, DefaultTolerance, tt.now))
+		})
+	}
+}
+
+func TestVerify_BlankSecret(t *testing.T) {
+	// Anyone can sign a request with a blank key, so it must never be accepted.
+	body := []byte(`{"event":"channel.created"}`)
+	h := http.Header{}
+	h.Set(TimestampHeader, strconv.FormatInt(testTime.Unix(), 10))
+	h.Set(SignatureHeader, Sign("", testTime, body))
+	assert.Equal(t, ErrInvalidSignature, Verify("", h, body, DefaultTolerance, testTime))
+
+	handler := NewHandler(Config{Clock: fixedClock(testTime)})
+	r, err := NewTestRequest("", EventChannelCreated, nil, testTime)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(Config{Secret: "secret", Clock: fixedClock(testTime)})
+	var got []string
+	h.OnDeployment(func(_ context.Context, e *Event, d *types.Deployment) error {
+		got = append(got, string(e.Event)+" "+d.ID)
+		return nil
+	})
+	h.OnBuild(func(_ context.Context, e *Event, b *types.Build) error {
+		got = append(got, string(e.Event)+" "+b.ID)
+		return nil
+	})
+	h.OnRollout(func(_ context.Context, e *Event, r *types.DeploymentRollout) error {
+		got = append(got, string(e.Event)+" "+r.ID)
+		return nil
+	})
+	h.OnContainer(func(_ context.Context, e *Event, c *types.Container) error {
+		if c.ID == "container_fail" {
+			return errors.New("boom")
+		}
+		got = append(got, string(e.Event)+" "+c.ID)
+		return nil
+	})
+	h.OnChannel(func(_ context.Context, e *Event, c *types.Channel) error {
+		got = append(got, string(e.Event)+" "+c.ID)
+		return nil
+	})
+
+	tests := []struct {
+		name string
+
+		event      EventName
+		data       any
+		want       string
+		wantStatus int
+	}{
+		{
+			name:       "deployment",
+			event:      EventDeploymentUpdated,
+			data:       types.Deployment{ID: "deployment_1"},
+			want:       "ignite.deployment.updated deployment_1",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "build",
+			event:      EventBuildCreated,
+			data:       types.Build{ID: "build_1"},
+			want:       "ignite.deployment.build.created build_1",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "rollout",
+			event:      EventRolloutUpdated,
+			data:       types.DeploymentRollout{ID: "rollout_1"},
+			want:       "ignite.deployment.rollout.updated rollout_1",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "container",
+			event:      EventContainerDeleted,
+			data:       types.Container{ID: "container_1"},
+			want:       "ignite.container.deleted container_1",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "channel",
+			event:      EventChannelCreated,
+			data:       types.Channel{ChannelPartial: types.ChannelPartial{ID: "channel_1"}},
+			want:       "channel.created channel_1",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "unknown",
+			event:      "pipe.room.created",
+			data:       map[string]any{},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "handler error",
+			event:      EventContainerUpdated,
+			data:       types.Container{ID: "container_fail"},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "invalid data",
+			event:      EventContainerUpdated,
+			data:       "not a container",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			r, err := NewTestRequest("secret", tt.event, tt.data, testTime)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.want == "" {
+				assert.Nil(t, got)
+			} else {
+				assert.Equal(t, []string{tt.want}, got)
+			}
+		})
+	}
+}
+
+func TestHandler_Rejects(t *testing.T) {
+	h := NewHandler(Config{Secret: "secret", Clock: fixedClock(testTime)})
+	called := false
+	h.OnUnknown(func(context.Context, *Event) error {
+		called = true
+		return nil
+	})
+
+	r, err := NewTestRequest("other", EventChannelCreated, nil, testTime)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// A valid request which is replayed later should be rejected.
+	r, err = NewTestRequest("secret", EventChannelCreated, nil, testTime.Add(-time.Hour))
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// A body over the limit should be rejected as too large rather than as an invalid signature.
+	r, err = NewTestRequest("secret", EventChannelCreated, strings.Repeat("a", maxBodySize), testTime)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	r = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.False(t, called)
+
+	r, err = NewTestRequest("secret", EventChannelCreated, nil, testTime)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.True(t, called)
+}